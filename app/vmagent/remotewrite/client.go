@@ -36,7 +36,7 @@ var (
 
 	rateLimit = flagutil.NewArrayInt("remoteWrite.rateLimit", 0, "Optional rate limit in bytes per second for data sent to the corresponding -remoteWrite.url. "+
 		"By default, the rate limit is disabled. It can be useful for limiting load on remote storage when big amounts of buffered data "+
-		"is sent after temporary unavailability of the remote storage. See also -maxIngestionRate")
+		"is sent after temporary unavailability of the remote storage. See also -maxIngestionRate and -remoteWrite.rateLimitConfig")
 	sendTimeout      = flagutil.NewArrayDuration("remoteWrite.sendTimeout", time.Minute, "Timeout for sending a single block of data to the corresponding -remoteWrite.url")
 	retryMinInterval = flagutil.NewArrayDuration("remoteWrite.retryMinInterval", time.Second, "The minimum delay between retry attempts to send a block of data to the corresponding -remoteWrite.url. Every next retry attempt will double the delay to prevent hammering of remote database. See also -remoteWrite.retryMaxInterval")
 	// deprecated in the future. use -remoteWrite.retryMaxInterval instead
@@ -88,6 +88,7 @@ var (
 )
 
 type client struct {
+	argIdx         int
 	sanitizedURL   string
 	remoteWriteURL string
 
@@ -158,6 +159,7 @@ func newHTTPClient(argIdx int, remoteWriteURL, sanitizedURL string, fq *persiste
 		retryMaxIntervalFlag = retryMaxInterval
 	}
 	c := &client{
+		argIdx:           argIdx,
 		sanitizedURL:     sanitizedURL,
 		remoteWriteURL:   remoteWriteURL,
 		authCfg:          authCfg,
@@ -191,9 +193,16 @@ func (c *client) init(argIdx, concurrency int, sanitizedURL string) {
 		logger.Infof("applying %d bytes per second rate limit for -remoteWrite.url=%q", bytesPerSec, sanitizedURL)
 		c.rl = ratelimiter.New(int64(bytesPerSec), limitReached, c.stopCh)
 	}
+	if path := rateLimitConfigPaths.GetOptionalArg(argIdx); path != "" {
+		logger.Infof("applying rate limit schedules from -remoteWrite.rateLimitConfig=%q for -remoteWrite.url=%q", path, sanitizedURL)
+		c.rl = ratelimiter.New(getRateLimit(argIdx, time.Now()), limitReached, c.stopCh)
+	}
 	c.bytesSent = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_bytes_sent_total{url=%q}`, c.sanitizedURL))
 	c.blocksSent = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_blocks_sent_total{url=%q}`, c.sanitizedURL))
 	c.rateLimit = metrics.GetOrCreateGauge(fmt.Sprintf(`vmagent_remotewrite_rate_limit{url=%q}`, c.sanitizedURL), func() float64 {
+		if c.rl != nil {
+			return float64(c.rl.Limit())
+		}
 		return float64(rateLimit.GetOptionalArg(argIdx))
 	})
 	c.requestDuration = metrics.GetOrCreateHistogram(fmt.Sprintf(`vmagent_remotewrite_duration_seconds{url=%q}`, c.sanitizedURL))
@@ -409,6 +418,10 @@ func (c *client) newRequest(url string, body []byte) (*http.Request, error) {
 // The function returns false only if c.stopCh is closed.
 // Otherwise, it tries sending the block to remote storage indefinitely.
 func (c *client) sendBlockHTTP(block []byte) bool {
+	if c.rl != nil {
+		// The rate limit may change over time according to -remoteWrite.rateLimitConfig
+		c.rl.SetLimit(getRateLimit(c.argIdx, time.Now()))
+	}
 	c.rl.Register(len(block))
 	maxRetryDuration := timeutil.AddJitterToDuration(c.retryMaxInterval)
 	retryDuration := timeutil.AddJitterToDuration(c.retryMinInterval)
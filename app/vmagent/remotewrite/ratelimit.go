@@ -0,0 +1,244 @@
+package remotewrite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var rateLimitConfigPaths = flagutil.NewArrayString("remoteWrite.rateLimitConfig", "Optional path to file with time-of-day rate limit schedules "+
+	"for the corresponding -remoteWrite.url. The rate limit from -remoteWrite.rateLimit is used outside the configured schedules. "+
+	"The file is re-read on SIGHUP signal. The path can point either to local file or to http url. "+
+	"See https://docs.victoriametrics.com/victoriametrics/vmagent/#rate-limiting")
+
+// rateLimitScheduleConfig is a single entry in the file pointed by -remoteWrite.rateLimitConfig.
+type rateLimitScheduleConfig struct {
+	// Days contains days of week when the schedule is active. The schedule is active every day if Days is empty.
+	Days []string `yaml:"days,omitempty"`
+
+	// Start and End contain the time of day in the form hh:mm when the schedule is active.
+	// The schedule spans midnight if End is smaller than Start.
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+
+	// Timezone is an optional timezone for Days, Start and End. Local timezone is used by default.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// RateLimit is the rate limit in bytes per second during the schedule. Zero value disables rate limiting.
+	RateLimit string `yaml:"rate_limit"`
+}
+
+type rateLimitSchedule struct {
+	days  [7]bool
+	start int
+	end   int
+	loc   *time.Location
+	limit int64
+}
+
+// rateLimitSchedules contains parsed schedules from -remoteWrite.rateLimitConfig file.
+type rateLimitSchedules struct {
+	schedules []rateLimitSchedule
+}
+
+// getRateLimit returns the rate limit for the given t.
+//
+// defaultLimit is returned if t doesn't match any schedule. The first matching schedule wins.
+func (rss *rateLimitSchedules) getRateLimit(t time.Time, defaultLimit int64) int64 {
+	if rss == nil {
+		return defaultLimit
+	}
+	for i := range rss.schedules {
+		rs := &rss.schedules[i]
+		if rs.matches(t) {
+			return rs.limit
+		}
+	}
+	return defaultLimit
+}
+
+func (rs *rateLimitSchedule) matches(t time.Time) bool {
+	t = t.In(rs.loc)
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if rs.start <= rs.end {
+		return rs.days[day] && minute >= rs.start && minute < rs.end
+	}
+	// The schedule spans midnight, so the part after midnight belongs to the previous day.
+	if minute >= rs.start {
+		return rs.days[day]
+	}
+	if minute < rs.end {
+		return rs.days[(day+6)%7]
+	}
+	return false
+}
+
+func loadRateLimitSchedules(path string) (*rateLimitSchedules, error) {
+	data, err := fscore.ReadFileOrHTTP(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rate limit schedules: %w", err)
+	}
+	data, err = envtemplate.ReplaceBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot expand environment vars: %w", err)
+	}
+	return parseRateLimitSchedules(data)
+}
+
+func parseRateLimitSchedules(data []byte) (*rateLimitSchedules, error) {
+	var cfgs []rateLimitScheduleConfig
+	if err := yaml.UnmarshalStrict(data, &cfgs); err != nil {
+		return nil, err
+	}
+	rss := &rateLimitSchedules{
+		schedules: make([]rateLimitSchedule, len(cfgs)),
+	}
+	for i := range cfgs {
+		if err := rss.schedules[i].init(&cfgs[i]); err != nil {
+			return nil, fmt.Errorf("cannot parse schedule #%d: %w", i+1, err)
+		}
+	}
+	return rss, nil
+}
+
+func (rs *rateLimitSchedule) init(cfg *rateLimitScheduleConfig) error {
+	if len(cfg.Days) == 0 {
+		for i := range rs.days {
+			rs.days[i] = true
+		}
+	}
+	for _, s := range cfg.Days {
+		day, ok := weekdays[strings.ToLower(s)]
+		if !ok {
+			return fmt.Errorf("unexpected day %q; supported values: mon, tue, wed, thu, fri, sat, sun", s)
+		}
+		rs.days[day] = true
+	}
+
+	start, err := parseTimeOfDay(cfg.Start)
+	if err != nil {
+		return fmt.Errorf("cannot parse `start`: %w", err)
+	}
+	end, err := parseTimeOfDay(cfg.End)
+	if err != nil {
+		return fmt.Errorf("cannot parse `end`: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("`start` and `end` cannot be equal; got %q", cfg.Start)
+	}
+	rs.start = start
+	rs.end = end
+
+	rs.loc = time.Local
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return fmt.Errorf("cannot load `timezone`: %w", err)
+		}
+		rs.loc = loc
+	}
+
+	limit, err := flagutil.ParseBytes(cfg.RateLimit)
+	if err != nil {
+		return fmt.Errorf("cannot parse `rate_limit`: %w", err)
+	}
+	if limit < 0 {
+		return fmt.Errorf("`rate_limit` cannot be negative; got %d", limit)
+	}
+	rs.limit = limit
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseTimeOfDay parses s in the form hh:mm and returns the number of minutes since the midnight.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q in the form hh:mm: %w", s, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// CheckRateLimitConfigs checks -remoteWrite.rateLimitConfig.
+func CheckRateLimitConfigs() error {
+	_, err := loadRateLimitConfigs()
+	return err
+}
+
+func initRateLimitConfigs() {
+	rlcs, err := loadRateLimitConfigs()
+	if err != nil {
+		logger.Fatalf("cannot initialize rate limit configs: %s", err)
+	}
+	allRateLimitConfigs.Store(&rlcs)
+}
+
+func reloadRateLimitConfigs() {
+	if !hasRateLimitConfigs() {
+		return
+	}
+	logger.Infof("reloading rate limit configs pointed by -remoteWrite.rateLimitConfig")
+	rlcs, err := loadRateLimitConfigs()
+	if err != nil {
+		logger.Errorf("cannot reload rate limit configs; preserving the previous configs; error: %s", err)
+		return
+	}
+	allRateLimitConfigs.Store(&rlcs)
+	logger.Infof("successfully reloaded rate limit configs")
+}
+
+func loadRateLimitConfigs() ([]*rateLimitSchedules, error) {
+	if len(*rateLimitConfigPaths) > len(*remoteWriteURLs) {
+		return nil, fmt.Errorf("too many -remoteWrite.rateLimitConfig args: %d; it mustn't exceed the number of -remoteWrite.url args: %d",
+			len(*rateLimitConfigPaths), len(*remoteWriteURLs))
+	}
+	rlcs := make([]*rateLimitSchedules, len(*remoteWriteURLs))
+	for i, path := range *rateLimitConfigPaths {
+		if len(path) == 0 {
+			// Skip empty rate limit config.
+			continue
+		}
+		rss, err := loadRateLimitSchedules(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load -remoteWrite.rateLimitConfig=%q: %w", path, err)
+		}
+		rlcs[i] = rss
+	}
+	return rlcs, nil
+}
+
+func hasRateLimitConfigs() bool {
+	for _, path := range *rateLimitConfigPaths {
+		if len(path) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getRateLimit returns the current rate limit in bytes per second for -remoteWrite.url at argIdx.
+func getRateLimit(argIdx int, t time.Time) int64 {
+	defaultLimit := int64(rateLimit.GetOptionalArg(argIdx))
+	p := allRateLimitConfigs.Load()
+	if p == nil || argIdx >= len(*p) {
+		return defaultLimit
+	}
+	return (*p)[argIdx].getRateLimit(t, defaultLimit)
+}
@@ -0,0 +1,133 @@
+package remotewrite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitSchedulesFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		if _, err := parseRateLimitSchedules([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// Invalid yaml
+	f(`foo`)
+
+	// Unknown field
+	f(`
+- start: "09:00"
+  end: "18:00"
+  rate_limit: 1MB
+  foo: bar
+`)
+
+	// Invalid days
+	f(`
+- days: [monday]
+  start: "09:00"
+  end: "18:00"
+  rate_limit: 1MB
+`)
+
+	// Invalid start and end
+	f(`
+- start: "9am"
+  end: "18:00"
+  rate_limit: 1MB
+`)
+	f(`
+- start: "09:00"
+  end: "25:00"
+  rate_limit: 1MB
+`)
+	f(`
+- start: "09:00"
+  end: "09:00"
+  rate_limit: 1MB
+`)
+
+	// Invalid timezone
+	f(`
+- start: "09:00"
+  end: "18:00"
+  timezone: Foo/Bar
+  rate_limit: 1MB
+`)
+
+	// Invalid rate_limit
+	f(`
+- start: "09:00"
+  end: "18:00"
+`)
+	f(`
+- start: "09:00"
+  end: "18:00"
+  rate_limit: foo
+`)
+	f(`
+- start: "09:00"
+  end: "18:00"
+  rate_limit: -1
+`)
+}
+
+func TestRateLimitSchedulesGetRateLimit(t *testing.T) {
+	rss, err := parseRateLimitSchedules([]byte(`
+- days: [Mon, tue, wed, thu, fri]
+  start: "09:00"
+  end: "18:00"
+  timezone: UTC
+  rate_limit: 1MB
+- days: [sat]
+  start: "22:00"
+  end: "02:00"
+  timezone: UTC
+  rate_limit: 0
+- start: "00:00"
+  end: "06:00"
+  timezone: UTC
+  rate_limit: 10KiB
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f := func(timestamp string, limitExpected int64) {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			t.Fatalf("cannot parse timestamp: %s", err)
+		}
+		limit := rss.getRateLimit(ts, 123)
+		if limit != limitExpected {
+			t.Fatalf("unexpected rate limit at %s; got %d; want %d", timestamp, limit, limitExpected)
+		}
+	}
+
+	// 2025-01-06 is Monday
+	f("2025-01-06T08:59:59Z", 123)
+	f("2025-01-06T09:00:00Z", 1000*1000)
+	f("2025-01-06T17:59:59Z", 1000*1000)
+	f("2025-01-06T18:00:00Z", 123)
+	f("2025-01-06T11:00:00+02:00", 1000*1000)
+
+	// Weekends
+	f("2025-01-11T12:00:00Z", 123)
+	f("2025-01-11T21:59:00Z", 123)
+	f("2025-01-11T23:00:00Z", 0)
+	f("2025-01-12T01:59:00Z", 0)
+
+	// The first matching schedule wins
+	f("2025-01-12T02:00:00Z", 10*1024)
+	f("2025-01-12T05:59:00Z", 10*1024)
+	f("2025-01-12T06:00:00Z", 123)
+
+	// Nil schedules
+	var rssEmpty *rateLimitSchedules
+	if limit := rssEmpty.getRateLimit(time.Now(), 123); limit != 123 {
+		t.Fatalf("unexpected rate limit for nil schedules; got %d; want %d", limit, 123)
+	}
+}
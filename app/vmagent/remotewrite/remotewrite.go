@@ -122,6 +122,9 @@ func MultitenancyEnabled() bool {
 // Contains the current relabelConfigs.
 var allRelabelConfigs atomic.Pointer[relabelConfigs]
 
+// allRateLimitConfigs contains rate limit schedules per each -remoteWrite.url
+var allRateLimitConfigs atomic.Pointer[[]*rateLimitSchedules]
+
 // Contains the current global stream aggregators.
 var sasGlobal atomic.Pointer[streamaggr.Aggregators]
 
@@ -198,6 +201,8 @@ func Init() {
 
 	initRelabelConfigs()
 
+	initRateLimitConfigs()
+
 	initStreamAggrConfigGlobal()
 
 	initRemoteWriteCtxs(*remoteWriteURLs)
@@ -224,6 +229,7 @@ func Init() {
 			case <-sighupCh:
 			}
 			reloadRelabelConfigs()
+			reloadRateLimitConfigs()
 			reloadStreamAggrConfigs()
 		}
 	}()
@@ -28,6 +28,9 @@ type Group struct {
 	Name       string             `yaml:"name"`
 	Interval   *promutil.Duration `yaml:"interval,omitempty"`
 	EvalOffset *promutil.Duration `yaml:"eval_offset,omitempty"`
+	// EvalJitter spreads evaluations of groups with the same eval_offset
+	// over the time range [eval_offset ... eval_offset+eval_jitter].
+	EvalJitter *promutil.Duration `yaml:"eval_jitter,omitempty"`
 	// EvalDelay will adjust the `time` parameter of rule evaluation requests to compensate intentional query delay from datasource.
 	// see https://github.com/VictoriaMetrics/VictoriaMetrics/issues/5155
 	EvalDelay   *promutil.Duration `yaml:"eval_delay,omitempty"`
@@ -88,6 +91,18 @@ func (g *Group) Validate(validateTplFn ValidateTplFn, validateExpressions bool)
 	if g.EvalOffset.Duration() > g.Interval.Duration() {
 		return fmt.Errorf("eval_offset should be smaller than interval; now eval_offset: %v, interval: %v", g.EvalOffset.Duration(), g.Interval.Duration())
 	}
+	if g.EvalJitter != nil {
+		if g.EvalJitter.Duration() < 0 {
+			return fmt.Errorf("eval_jitter shouldn't be lower than 0")
+		}
+		if g.EvalOffset == nil {
+			return fmt.Errorf("eval_jitter can be used only together with eval_offset")
+		}
+		if g.EvalOffset.Duration()+g.EvalJitter.Duration() > g.Interval.Duration() {
+			return fmt.Errorf("eval_offset+eval_jitter should be smaller than interval; now eval_offset: %v, eval_jitter: %v, interval: %v",
+				g.EvalOffset.Duration(), g.EvalJitter.Duration(), g.Interval.Duration())
+		}
+	}
 	if g.EvalOffset != nil && g.EvalDelay != nil {
 		return fmt.Errorf("eval_offset cannot be used with eval_delay")
 	}
@@ -141,9 +156,12 @@ type Rule struct {
 	For    *promutil.Duration `yaml:"for,omitempty"`
 	// Alert will continue firing for this long even when the alerting expression no longer has results.
 	KeepFiringFor *promutil.Duration `yaml:"keep_firing_for,omitempty"`
-	Labels        map[string]string  `yaml:"labels,omitempty"`
-	Annotations   map[string]string  `yaml:"annotations,omitempty"`
-	Debug         *bool              `yaml:"debug,omitempty"`
+	// Absent makes alerting rule to fire when its expression returns no results.
+	// It is useful for detecting absence of logs for `vlogs` rules.
+	Absent      bool              `yaml:"absent,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Debug       *bool             `yaml:"debug,omitempty"`
 	// UpdateEntriesLimit defines max number of rule's state updates stored in memory.
 	// Overrides `-rule.updateEntriesLimit`.
 	UpdateEntriesLimit *int `yaml:"update_entries_limit,omitempty"`
@@ -207,6 +225,9 @@ func HashRule(r Rule) uint64 {
 	} else {
 		h.Write([]byte("alerting"))
 		h.Write([]byte(r.Alert))
+		if r.Absent {
+			h.Write([]byte("absent"))
+		}
 	}
 	kv := sortMap(r.Labels)
 	for _, i := range kv {
@@ -225,6 +246,9 @@ func (r *Rule) Validate() error {
 	if r.Expr == "" {
 		return fmt.Errorf("expression can't be empty")
 	}
+	if r.Absent && r.Record != "" {
+		return fmt.Errorf("`absent` can be used only for alerting rules")
+	}
 	return checkOverflow(r.XXX, "rule")
 }
 
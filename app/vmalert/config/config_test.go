@@ -139,6 +139,12 @@ func TestRuleValidate(t *testing.T) {
 	if err := (&Rule{Alert: "alert", Expr: "test>0"}).Validate(); err != nil {
 		t.Fatalf("expected valid rule; got %s", err)
 	}
+	if err := (&Rule{Alert: "alert", Expr: "* | stats count()", Absent: true}).Validate(); err != nil {
+		t.Fatalf("expected valid rule; got %s", err)
+	}
+	if err := (&Rule{Record: "record", Expr: "* | stats count()", Absent: true}).Validate(); err == nil {
+		t.Fatalf("expected error for absent recording rule")
+	}
 }
 
 func TestGroupValidate_Failure(t *testing.T) {
@@ -181,6 +187,19 @@ func TestGroupValidate_Failure(t *testing.T) {
 		EvalOffset: promutil.NewDuration(2 * time.Minute),
 	}, false, "eval_offset should be smaller than interval")
 
+	f(&Group{
+		Name:       "eval_jitter without eval_offset",
+		Interval:   promutil.NewDuration(time.Minute),
+		EvalJitter: promutil.NewDuration(10 * time.Second),
+	}, false, "eval_jitter can be used only together with eval_offset")
+
+	f(&Group{
+		Name:       "wrong eval_jitter",
+		Interval:   promutil.NewDuration(time.Minute),
+		EvalOffset: promutil.NewDuration(30 * time.Second),
+		EvalJitter: promutil.NewDuration(40 * time.Second),
+	}, false, "eval_offset+eval_jitter should be smaller than interval")
+
 	f(&Group{
 		Name:  "wrong limit",
 		Limit: -1,
@@ -30,6 +30,8 @@ type AlertManager struct {
 	authCfg *promauth.Config
 	// stores already parsed RelabelConfigs object
 	relabelConfigs *promrelabel.ParsedConfigs
+	// optional selector for alerts, which must be sent to the AlertManager
+	match *promrelabel.IfExpression
 
 	metrics *notifierMetrics
 }
@@ -92,9 +94,17 @@ func (am *AlertManager) send(ctx context.Context, alerts []Alert, headers map[st
 		if len(lbls) == 0 {
 			continue
 		}
+		if !am.match.Match(lbls) {
+			// The alert must be routed to other notifiers
+			continue
+		}
 		alertsToSend = append(alertsToSend, a)
 		lblss = append(lblss, lbls)
 	}
+	if len(alertsToSend) == 0 && am.match != nil {
+		// Do not send empty requests to the AlertManager if all the alerts are routed to other notifiers
+		return nil
+	}
 	writeamRequest(b, alertsToSend, am.argFunc, lblss)
 
 	req, err := http.NewRequest(http.MethodPost, am.addr.String(), b)
@@ -25,6 +25,64 @@ func TestAlertManager_Addr(t *testing.T) {
 	}
 }
 
+func TestAlertManager_SendMatch(t *testing.T) {
+	var alertNames []string
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc(alertManagerPath, func(_ http.ResponseWriter, r *http.Request) {
+		requests++
+		var a []struct {
+			Labels map[string]string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Fatalf("can not unmarshal data into alert %s", err)
+		}
+		for _, alert := range a {
+			alertNames = append(alertNames, alert.Labels["alertname"])
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	am, err := NewAlertManager(srv.URL+alertManagerPath, func(_ Alert) string { return "" }, promauth.HTTPClientConfig{}, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	am.match = &promrelabel.IfExpression{}
+	if err := am.match.Parse(`{team="a"}`); err != nil {
+		t.Fatalf("unexpected error when parsing match: %s", err)
+	}
+
+	if err := am.Send(context.Background(), []Alert{
+		{
+			Labels: map[string]string{"alertname": "alert1", "team": "a"},
+		},
+		{
+			Labels: map[string]string{"alertname": "alert2", "team": "b"},
+		},
+		{
+			Labels: map[string]string{"alertname": "alert3"},
+		},
+	}, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(alertNames) != 1 || alertNames[0] != "alert1" {
+		t.Fatalf("unexpected alerts sent; got %q; want %q", alertNames, []string{"alert1"})
+	}
+
+	// No requests must be sent if there are no matching alerts
+	if err := am.Send(context.Background(), []Alert{
+		{
+			Labels: map[string]string{"alertname": "alert2", "team": "b"},
+		},
+	}, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if requests != 1 {
+		t.Fatalf("unexpected number of requests; got %d; want %d", requests, 1)
+	}
+}
+
 func TestAlertManager_Send(t *testing.T) {
 	const baUser, baPass = "foo", "bar"
 	const headerKey, headerValue = "TenantID", "foo"
@@ -29,10 +29,10 @@ type Config struct {
 
 	// ConsulSDConfigs contains list of settings for service discovery via Consul
 	// see https://prometheus.io/docs/prometheus/latest/configuration/configuration/#consul_sd_config
-	ConsulSDConfigs []consul.SDConfig `yaml:"consul_sd_configs,omitempty"`
+	ConsulSDConfigs []ConsulSDConfig `yaml:"consul_sd_configs,omitempty"`
 	// DNSSDConfigs contains list of settings for service discovery via DNS.
 	// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#dns_sd_config
-	DNSSDConfigs []dns.SDConfig `yaml:"dns_sd_configs,omitempty"`
+	DNSSDConfigs []DNSSDConfig `yaml:"dns_sd_configs,omitempty"`
 
 	// StaticConfigs contains list of static targets
 	StaticConfigs []StaticConfig `yaml:"static_configs,omitempty"`
@@ -66,10 +66,27 @@ type Config struct {
 //
 //	targets:
 //	[ - '<host>' ]
+//	[ match: '<series_selector>' ]
 type StaticConfig struct {
 	Targets []string `yaml:"targets"`
 	// HTTPClientConfig contains HTTP configuration for the Targets
 	HTTPClientConfig promauth.HTTPClientConfig `yaml:",inline"`
+	// Match is an optional series selector for alerts, which must be sent to the Targets
+	Match *promrelabel.IfExpression `yaml:"match,omitempty"`
+}
+
+// ConsulSDConfig contains settings for service discovery via Consul
+type ConsulSDConfig struct {
+	consul.SDConfig `yaml:",inline"`
+	// Match is an optional series selector for alerts, which must be sent to the discovered targets
+	Match *promrelabel.IfExpression `yaml:"match,omitempty"`
+}
+
+// DNSSDConfig contains settings for service discovery via DNS
+type DNSSDConfig struct {
+	dns.SDConfig `yaml:",inline"`
+	// Match is an optional series selector for alerts, which must be sent to the discovered targets
+	Match *promrelabel.IfExpression `yaml:"match,omitempty"`
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
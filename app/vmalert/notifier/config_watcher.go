@@ -8,6 +8,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape/discovery/consul"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape/discovery/dns"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutil"
@@ -119,15 +120,22 @@ func (cw *configWatcher) add(typeK TargetType, interval time.Duration, labelsFn
 	return nil
 }
 
-func getTargetMetadata(labelsFn getLabels, cfg *Config) (map[string]*promutil.Labels, []error) {
+// targetMetadata contains labels and match for the target discovered via service discovery
+type targetMetadata struct {
+	labels *promutil.Labels
+	match  *promrelabel.IfExpression
+}
+
+func getTargetMetadata(labelsFn getLabels, cfg *Config) (map[string]targetMetadata, []error) {
 	metaLabels, err := labelsFn()
 	if err != nil {
 		return nil, []error{fmt.Errorf("failed to get labels: %w", err)}
 	}
-	targetMetadata := make(map[string]*promutil.Labels, len(metaLabels))
+	tms := make(map[string]targetMetadata, len(metaLabels))
 	var errors []error
 	duplicates := make(map[string]struct{})
-	for _, labels := range metaLabels {
+	for _, tm := range metaLabels {
+		labels := tm.labels
 		target := labels.Get("__address__")
 		u, processedLabels, err := parseLabels(target, labels, cfg)
 		if err != nil {
@@ -147,12 +155,16 @@ func getTargetMetadata(labelsFn getLabels, cfg *Config) (map[string]*promutil.La
 			continue
 		}
 		duplicates[u] = struct{}{}
-		targetMetadata[u] = processedLabels
+		tms[u] = targetMetadata{
+			labels: processedLabels,
+			match:  tm.match,
+		}
 	}
-	return targetMetadata, errors
+	return tms, errors
 }
 
-type getLabels func() ([]*promutil.Labels, error)
+// getLabels must return labels with the corresponding match for every discovered target
+type getLabels func() ([]targetMetadata, error)
 
 func (cw *configWatcher) start() error {
 	if len(cw.cfg.StaticConfigs) > 0 {
@@ -168,6 +180,7 @@ func (cw *configWatcher) start() error {
 				if err != nil {
 					return fmt.Errorf("failed to init alertmanager for addr %q: %w", address, err)
 				}
+				notifier.match = cfg.Match
 				targets = append(targets, Target{
 					Notifier: notifier,
 					Labels:   labels,
@@ -178,17 +191,22 @@ func (cw *configWatcher) start() error {
 	}
 
 	if len(cw.cfg.ConsulSDConfigs) > 0 {
-		err := cw.add(TargetConsul, *consul.SDCheckInterval, func() ([]*promutil.Labels, error) {
-			var labels []*promutil.Labels
+		err := cw.add(TargetConsul, *consul.SDCheckInterval, func() ([]targetMetadata, error) {
+			var tms []targetMetadata
 			for i := range cw.cfg.ConsulSDConfigs {
 				sdc := &cw.cfg.ConsulSDConfigs[i]
 				targetLabels, err := sdc.GetLabels(cw.cfg.baseDir)
 				if err != nil {
 					return nil, fmt.Errorf("got labels err: %w", err)
 				}
-				labels = append(labels, targetLabels...)
+				for _, labels := range targetLabels {
+					tms = append(tms, targetMetadata{
+						labels: labels,
+						match:  sdc.Match,
+					})
+				}
 			}
-			return labels, nil
+			return tms, nil
 		})
 		if err != nil {
 			return fmt.Errorf("failed to start consulSD discovery: %w", err)
@@ -196,17 +214,22 @@ func (cw *configWatcher) start() error {
 	}
 
 	if len(cw.cfg.DNSSDConfigs) > 0 {
-		err := cw.add(TargetDNS, *dns.SDCheckInterval, func() ([]*promutil.Labels, error) {
-			var labels []*promutil.Labels
+		err := cw.add(TargetDNS, *dns.SDCheckInterval, func() ([]targetMetadata, error) {
+			var tms []targetMetadata
 			for i := range cw.cfg.DNSSDConfigs {
 				sdc := &cw.cfg.DNSSDConfigs[i]
 				targetLabels, err := sdc.GetLabels(cw.cfg.baseDir)
 				if err != nil {
 					return nil, fmt.Errorf("got labels err: %w", err)
 				}
-				labels = append(labels, targetLabels...)
+				for _, labels := range targetLabels {
+					tms = append(tms, targetMetadata{
+						labels: labels,
+						match:  sdc.Match,
+					})
+				}
 			}
-			return labels, nil
+			return tms, nil
 		})
 		if err != nil {
 			return fmt.Errorf("failed to start DNSSD discovery: %w", err)
@@ -240,7 +263,7 @@ func (cw *configWatcher) setTargets(key TargetType, targets []Target) {
 	cw.targetsMu.Unlock()
 }
 
-func (cw *configWatcher) updateTargets(key TargetType, targetMetadata map[string]*promutil.Labels, cfg *Config, genFn AlertURLGenerator) {
+func (cw *configWatcher) updateTargets(key TargetType, targetMetadata map[string]targetMetadata, cfg *Config, genFn AlertURLGenerator) {
 	cw.targetsMu.Lock()
 	defer cw.targetsMu.Unlock()
 	oldTargets := cw.targets[key]
@@ -255,15 +278,16 @@ func (cw *configWatcher) updateTargets(key TargetType, targetMetadata map[string
 		}
 	}
 	// create new resources for the new targets
-	for addr, labels := range targetMetadata {
+	for addr, tm := range targetMetadata {
 		am, err := NewAlertManager(addr, genFn, cfg.HTTPClientConfig, cfg.parsedAlertRelabelConfigs, cfg.Timeout.Duration())
 		if err != nil {
 			logger.Errorf("failed to init %s notifier with addr %q: %w", key, addr, err)
 			continue
 		}
+		am.match = tm.match
 		updatedTargets = append(updatedTargets, Target{
 			Notifier: am,
-			Labels:   labels,
+			Labels:   tm.labels,
 		})
 	}
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape/discovery/consul"
 )
 
@@ -112,6 +113,51 @@ consul_sd_configs:
 	}
 }
 
+func TestConfigWatcherSDMatch(t *testing.T) {
+	consulSDServer := newFakeConsulServer()
+	defer consulSDServer.Close()
+
+	consulSDFile, err := os.CreateTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.MustRemovePath(consulSDFile.Name())
+
+	writeToFile(consulSDFile.Name(), fmt.Sprintf(`
+consul_sd_configs:
+  - server: %s
+    services:
+      - alertmanager
+    match: '{team="a"}'
+`, consulSDServer.URL))
+
+	cw, err := newWatcher(consulSDFile.Name(), nil)
+	if err != nil {
+		t.Fatalf("failed to start config watcher: %s", err)
+	}
+	defer cw.mustStop()
+
+	ns := cw.notifiers()
+	if len(ns) == 0 {
+		t.Fatalf("expected to get discovered notifiers")
+	}
+	for _, n := range ns {
+		am, ok := n.(*AlertManager)
+		if !ok {
+			t.Fatalf("unexpected notifier type %T", n)
+		}
+		if am.match == nil {
+			t.Fatalf("missing match for notifier %q", am.Addr())
+		}
+		if !am.match.Match([]prompb.Label{{Name: "team", Value: "a"}}) {
+			t.Fatalf("notifier %q must match alerts with team=\"a\"", am.Addr())
+		}
+		if am.match.Match([]prompb.Label{{Name: "team", Value: "b"}}) {
+			t.Fatalf("notifier %q mustn't match alerts with team=\"b\"", am.Addr())
+		}
+	}
+}
+
 // TestConfigWatcherReloadConcurrent supposed to test concurrent
 // execution of configuration update.
 // Should be executed with -race flag
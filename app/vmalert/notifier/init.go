@@ -9,6 +9,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutil"
 )
 
@@ -55,6 +56,9 @@ var (
 	oauth2Scopes = flagutil.NewArrayString("notifier.oauth2.scopes", "Optional OAuth2 scopes to use for -notifier.url. Scopes must be delimited by ';'. "+
 		"If multiple args are set, then they are applied independently for the corresponding -notifier.url")
 	sendTimeout = flagutil.NewArrayDuration("notifier.sendTimeout", 10*time.Second, "Timeout when sending alerts to the corresponding -notifier.url")
+	match       = flagutil.NewArrayString("notifier.match", "Optional series selector for alerts to send to the corresponding -notifier.url, e.g. '{team=\"a\"}'. "+
+		"Alerts with labels not matching the selector aren't sent to the corresponding -notifier.url. By default, all the alerts are sent to all the -notifier.url. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmalert/#notifier-routing")
 )
 
 // cw holds a configWatcher for configPath configuration file
@@ -180,6 +184,13 @@ func notifiersFromFlags(gen AlertURLGenerator) ([]Notifier, error) {
 		if err != nil {
 			return nil, err
 		}
+		if s := match.GetOptionalArg(i); s != "" {
+			var ie promrelabel.IfExpression
+			if err := ie.Parse(s); err != nil {
+				return nil, fmt.Errorf("cannot parse -notifier.match=%q: %w", s, err)
+			}
+			am.match = &ie
+		}
 		notifiers = append(notifiers, am)
 	}
 	return notifiers, nil
@@ -0,0 +1,157 @@
+package rule
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
+)
+
+var (
+	alertHistoryLimit = flag.Int("rule.alertHistoryLimit", 0, "Defines the max number of alert state transitions stored in-memory per alerting rule. "+
+		"Stored transitions are available via /api/v1/alerts/history. Zero value disables alert history. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmalert/#alert-history")
+	alertHistoryPersist = flag.Bool("rule.alertHistoryPersist", false, "Whether to write alert state transitions as `ALERTS_HISTORY` time series to -remoteWrite.url. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmalert/#alert-history")
+)
+
+const (
+	// alertHistoryMetricName is the metric name for time series reflecting alert state transitions.
+	alertHistoryMetricName = "ALERTS_HISTORY"
+
+	// alertPrevStateLabel is the label name indicating the state of an alert before the transition.
+	alertPrevStateLabel = "alertstate_previous"
+)
+
+// AlertTransition describes a single change of the alert state
+type AlertTransition struct {
+	// Time is the evaluation timestamp when transition happened
+	Time time.Time
+	// AlertID is the ID of the alert within the group
+	AlertID uint64
+	// Labels are the labels of the alert
+	Labels map[string]string
+	// From is the alert state before the transition
+	From notifier.AlertState
+	// To is the alert state after the transition.
+	// Transition from notifier.StateFiring to notifier.StateInactive means the alert was resolved.
+	To notifier.AlertState
+	// Value is the alert value at Time
+	Value float64
+	// Duration is the time the alert spent in From state
+	Duration time.Duration
+}
+
+// alertHistory stores the most recent alert state transitions
+type alertHistory struct {
+	mu          sync.RWMutex
+	transitions []AlertTransition
+	cur         int
+	full        bool
+}
+
+func newAlertHistory(limit int) *alertHistory {
+	if limit <= 0 {
+		return nil
+	}
+	return &alertHistory{
+		transitions: make([]AlertTransition, limit),
+	}
+}
+
+func (ah *alertHistory) add(at AlertTransition) {
+	if ah == nil {
+		return
+	}
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+
+	ah.transitions[ah.cur] = at
+	ah.cur++
+	if ah.cur == len(ah.transitions) {
+		ah.cur = 0
+		ah.full = true
+	}
+}
+
+// getAll returns stored transitions ordered from the oldest to the newest
+func (ah *alertHistory) getAll() []AlertTransition {
+	if ah == nil {
+		return nil
+	}
+	ah.mu.RLock()
+	defer ah.mu.RUnlock()
+
+	if !ah.full {
+		return append([]AlertTransition{}, ah.transitions[:ah.cur]...)
+	}
+	result := make([]AlertTransition, 0, len(ah.transitions))
+	result = append(result, ah.transitions[ah.cur:]...)
+	return append(result, ah.transitions[:ah.cur]...)
+}
+
+// GetHistory returns recent alert state transitions of the rule
+// ordered from the oldest to the newest.
+func (ar *AlertingRule) GetHistory() []AlertTransition {
+	return ar.history.getAll()
+}
+
+// newAlertTransition returns the transition of a between the given states at ts.
+// since is the moment when a entered the from state.
+func newAlertTransition(a *notifier.Alert, from, to notifier.AlertState, since, ts time.Time) AlertTransition {
+	var d time.Duration
+	if !since.IsZero() {
+		d = ts.Sub(since)
+	}
+	return AlertTransition{
+		Time:     ts,
+		AlertID:  a.ID,
+		Labels:   a.Labels,
+		From:     from,
+		To:       to,
+		Value:    a.Value,
+		Duration: d,
+	}
+}
+
+// recordTransitions registers the given transitions in ar history.
+//
+// Returned time series must be written to remote storage if -rule.alertHistoryPersist is set.
+func (ar *AlertingRule) recordTransitions(transitions []AlertTransition) []prompb.TimeSeries {
+	var tss []prompb.TimeSeries
+	for _, at := range transitions {
+		ar.history.add(at)
+		if *alertHistoryPersist {
+			tss = append(tss, alertTransitionToTimeSeries(at))
+		}
+	}
+	return tss
+}
+
+// alertTransitionToTimeSeries returns `ALERTS_HISTORY` time series
+// with the alert value at the moment of transition.
+func alertTransitionToTimeSeries(at AlertTransition) prompb.TimeSeries {
+	labels := make([]prompb.Label, 0, len(at.Labels)+3)
+	for k, v := range at.Labels {
+		labels = append(labels, prompb.Label{
+			Name:  k,
+			Value: v,
+		})
+	}
+	// __name__ already been dropped, no need to check duplication
+	labels = append(labels, prompb.Label{Name: "__name__", Value: alertHistoryMetricName})
+	if ol := promrelabel.GetLabelByName(labels, alertStateLabel); ol != nil {
+		ol.Value = at.To.String()
+	} else {
+		labels = append(labels, prompb.Label{Name: alertStateLabel, Value: at.To.String()})
+	}
+	if ol := promrelabel.GetLabelByName(labels, alertPrevStateLabel); ol != nil {
+		ol.Value = at.From.String()
+	} else {
+		labels = append(labels, prompb.Label{Name: alertPrevStateLabel, Value: at.From.String()})
+	}
+	return newTimeSeries([]float64{at.Value}, []int64{at.Time.Unix()}, labels)
+}
@@ -31,6 +31,7 @@ type AlertingRule struct {
 	Expr          string
 	For           time.Duration
 	KeepFiringFor time.Duration
+	Absent        bool
 	Labels        map[string]string
 	Annotations   map[string]string
 	GroupID       uint64
@@ -49,6 +50,10 @@ type AlertingRule struct {
 	// during evaluations
 	state *ruleState
 
+	// history stores recent alert state transitions.
+	// It is nil if -rule.alertHistoryLimit is zero.
+	history *alertHistory
+
 	metrics *alertingRuleMetrics
 }
 
@@ -136,6 +141,7 @@ func NewAlertingRule(qb datasource.QuerierBuilder, group *Group, cfg config.Rule
 		Expr:          cfg.Expr,
 		For:           cfg.For.Duration(),
 		KeepFiringFor: cfg.KeepFiringFor.Duration(),
+		Absent:        cfg.Absent,
 		Labels:        cfg.Labels,
 		Annotations:   cfg.Annotations,
 		GroupID:       group.GetID(),
@@ -164,6 +170,7 @@ func NewAlertingRule(qb datasource.QuerierBuilder, group *Group, cfg config.Rule
 	ar.state = &ruleState{
 		entries: make([]StateEntry, entrySize),
 	}
+	ar.history = newAlertHistory(*alertHistoryLimit)
 	return ar
 }
 
@@ -245,6 +252,7 @@ func (ar *AlertingRule) updateWith(r Rule) error {
 	ar.Expr = nr.Expr
 	ar.For = nr.For
 	ar.KeepFiringFor = nr.KeepFiringFor
+	ar.Absent = nr.Absent
 	ar.Labels = nr.Labels
 	ar.Annotations = nr.Annotations
 	ar.EvalInterval = nr.EvalInterval
@@ -329,6 +337,9 @@ func (ar *AlertingRule) toLabels(m datasource.Metric, qFn templates.QueryFn) (*l
 // It is not thread safe.
 // It returns ALERT and ALERT_FOR_STATE time series as a result.
 func (ar *AlertingRule) execRange(ctx context.Context, start, end time.Time) ([]prompb.TimeSeries, error) {
+	if ar.Absent {
+		return nil, fmt.Errorf("rules with `absent: true` aren't supported in replay mode")
+	}
 	res, err := ar.q.QueryRange(ctx, ar.Expr, start, end)
 	if err != nil {
 		return nil, err
@@ -417,6 +428,9 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 	}
 
 	ar.logDebugf(ts, nil, "query returned %d series (elapsed: %s, isPartial: %t)", curState.Samples, curState.Duration, isPartialResponse(res))
+	if ar.Absent {
+		res.Data = absentMetrics(ar.Type.Get(), res.Data, ts)
+	}
 	qFn := func(query string) ([]datasource.Metric, error) {
 		res, _, err := ar.q.Query(ctx, query, ts)
 		return res.Data, err
@@ -448,6 +462,10 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 		}
 	}
 
+	var tss []prompb.TimeSeries
+	// transitions are recorded only after the limit check,
+	// since the alerts are dropped if the limit is exceeded.
+	var transitions []AlertTransition
 	updated := make(map[uint64]struct{})
 	// update list of active alerts
 	for i, m := range res.Data {
@@ -460,15 +478,16 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 		}
 		updated[alertID] = struct{}{}
 		if a, ok := ar.alerts[alertID]; ok {
+			a.Value = m.Values[0]
 			if a.State == notifier.StateInactive {
 				// alert could be in inactive state for resolvedRetention
 				// so when we again receive metrics for it - we switch it
 				// back to notifier.StatePending
 				a.State = notifier.StatePending
 				a.ActiveAt = ts
+				transitions = append(transitions, newAlertTransition(a, notifier.StateInactive, notifier.StatePending, a.ResolvedAt, ts))
 				ar.logDebugf(ts, a, "INACTIVE => PENDING")
 			}
-			a.Value = m.Values[0]
 			a.Annotations = annotations
 			a.KeepFiringSince = time.Time{}
 			continue
@@ -477,10 +496,10 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 		a.ID = alertID
 		a.State = notifier.StatePending
 		ar.alerts[alertID] = a
+		transitions = append(transitions, newAlertTransition(a, notifier.StateInactive, notifier.StatePending, time.Time{}, ts))
 		ar.logDebugf(ts, a, "created in state PENDING")
 	}
 	var numActivePending int
-	var tss []prompb.TimeSeries
 	for h, a := range ar.alerts {
 		// if alert wasn't updated in this iteration
 		// means it is resolved already
@@ -489,6 +508,7 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 				// alert was in Pending state - it is not active anymore
 				// add stale time series
 				tss = append(tss, pendingAlertStaleTimeSeries(a.Labels, ts.Unix(), true)...)
+				transitions = append(transitions, newAlertTransition(a, notifier.StatePending, notifier.StateInactive, a.ActiveAt, ts))
 
 				delete(ar.alerts, h)
 				ar.logDebugf(ts, a, "PENDING => DELETED: is absent in current evaluation round")
@@ -509,6 +529,7 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 					a.ResolvedAt = ts
 					// add stale time series
 					tss = append(tss, firingAlertStaleTimeSeries(a.Labels, ts.Unix())...)
+					transitions = append(transitions, newAlertTransition(a, notifier.StateFiring, notifier.StateInactive, a.Start, ts))
 
 					ar.logDebugf(ts, a, "FIRING => INACTIVE: is absent in current evaluation round")
 					continue
@@ -521,6 +542,7 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 			a.State = notifier.StateFiring
 			a.Start = ts
 			alertsFired.Inc()
+			transitions = append(transitions, newAlertTransition(a, notifier.StatePending, notifier.StateFiring, a.ActiveAt, ts))
 			if ar.For > 0 {
 				// add stale time series
 				tss = append(tss, pendingAlertStaleTimeSeries(a.Labels, ts.Unix(), false)...)
@@ -533,9 +555,48 @@ func (ar *AlertingRule) exec(ctx context.Context, ts time.Time, limit int) ([]pr
 		curState.Err = fmt.Errorf("exec exceeded limit of %d with %d alerts", limit, numActivePending)
 		return nil, curState.Err
 	}
+	tss = append(tss, ar.recordTransitions(transitions)...)
 	return append(tss, ar.toTimeSeries(ts.Unix())...), nil
 }
 
+// absentMetrics returns a single metric with value 1 if data is empty.
+// Otherwise, it returns nil.
+//
+// It is used by rules with `absent: true`, which must fire when the expression returns no results.
+func absentMetrics(dType string, data []datasource.Metric, ts time.Time) []datasource.Metric {
+	if len(data) > 0 && !isVLogsEmptyStats(dType, data) {
+		return nil
+	}
+	return []datasource.Metric{{
+		Timestamps: []int64{ts.Unix()},
+		Values:     []float64{1},
+	}}
+}
+
+// isVLogsEmptyStats returns true if data is the result of `stats` pipe without `by(...)` fields over zero logs.
+//
+// VictoriaLogs returns a single row with zero values for such queries instead of empty result,
+// e.g. `_time:10m {app="nginx"} | stats count() as rows` returns `rows=0` if there are no matching logs.
+func isVLogsEmptyStats(dType string, data []datasource.Metric) bool {
+	if dType != "vlogs" {
+		return false
+	}
+	for _, m := range data {
+		for _, l := range m.Labels {
+			if l.Name != "stats_result" {
+				// the result contains `by(...)` fields
+				return false
+			}
+		}
+		for _, v := range m.Values {
+			if v != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (ar *AlertingRule) expandTemplates(m datasource.Metric, qFn templates.QueryFn, ts time.Time) (*labelSet, map[string]string, error) {
 	ls, err := ar.toLabels(m, qFn)
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"strings"
@@ -19,6 +21,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/vmalertutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutil"
 )
 
@@ -291,6 +294,18 @@ func TestAlertingRule_Exec(t *testing.T) {
 			},
 		})
 
+	absentRule := newTestAlertingRule("absent-firing=>inactive=>firing", 0)
+	absentRule.Absent = true
+	f(absentRule, [][]datasource.Metric{
+		{},
+		{metricWithLabels(t, "name", "foo")},
+		{},
+	}, map[int][]testAlert{
+		0: {{alert: &notifier.Alert{State: notifier.StateFiring}}},
+		1: {{alert: &notifier.Alert{State: notifier.StateInactive}}},
+		2: {{alert: &notifier.Alert{State: notifier.StateFiring}}},
+	}, nil)
+
 	f(newTestAlertingRule("single-firing=>inactive=>firing=>inactive=>inactive", 0), [][]datasource.Metric{
 		{metricWithLabels(t, "name", "foo")},
 		{},
@@ -1323,6 +1338,50 @@ func newTestRuleWithLabels(name string, labels ...string) *AlertingRule {
 	return r
 }
 
+func TestAlertingRule_AbsentVLogs(t *testing.T) {
+	f := func(response string, firingExpected bool) {
+		t.Helper()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(response))
+		}))
+		defer srv.Close()
+
+		c := datasource.NewPrometheusClient(srv.URL, nil, false, srv.Client())
+		ar := newTestAlertingRule("absent-vlogs", 0)
+		ar.Type = config.NewVLogsType()
+		ar.Expr = `_time:10m {app="nginx"} | stats count() as rows`
+		ar.Absent = true
+		ar.q = c.BuildWithParams(datasource.QuerierParams{DataSourceType: "vlogs", EvaluationInterval: time.Minute})
+
+		if _, err := ar.exec(context.TODO(), time.Now(), 0); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var firing bool
+		for _, a := range ar.alerts {
+			if a.State == notifier.StateFiring {
+				firing = true
+			}
+		}
+		if firing != firingExpected {
+			t.Fatalf("unexpected firing state; got %v; want %v", firing, firingExpected)
+		}
+	}
+
+	// empty result
+	f(`{"status":"success","data":{"resultType":"vector","result":[]}}`, true)
+
+	// stats without by(...) fields over zero logs
+	f(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"rows"},"value":[1700000000,"0"]}]}}`, true)
+	f(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"rows"},"value":[1700000000,"0"]},{"metric":{"__name__":"bytes"},"value":[1700000000,"0"]}]}}`, true)
+
+	// stats without by(...) fields over non-zero logs
+	f(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"rows"},"value":[1700000000,"12"]}]}}`, false)
+
+	// stats with by(...) fields
+	f(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"rows","host":"foo"},"value":[1700000000,"0"]}]}}`, false)
+}
+
 func newTestAlertingRule(name string, waitFor time.Duration) *AlertingRule {
 	rule := AlertingRule{
 		Name:         name,
@@ -1421,3 +1480,121 @@ func TestAlertingRuleExec_Partial(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 }
+
+func TestAlertingRule_History(t *testing.T) {
+	f := func(limit int, persist bool, transitionsExpected []AlertTransition) {
+		t.Helper()
+
+		defer func(v bool) {
+			*alertHistoryPersist = v
+		}(*alertHistoryPersist)
+		*alertHistoryPersist = persist
+
+		fq := &datasource.FakeQuerier{}
+		ar := newTestAlertingRule("test", time.Minute)
+		ar.q = fq
+		ar.history = newAlertHistory(limit)
+
+		ts, _ := time.Parse(time.RFC3339, "2024-10-29T00:00:00Z")
+		steps := []float64{1, 2, 0, 3, 0}
+		var historySeries int
+		for _, v := range steps {
+			fq.Reset()
+			if v > 0 {
+				fq.Add(metricWithValueAndLabels(t, v, "instance", "foo"))
+			}
+			tss, err := ar.exec(context.TODO(), ts, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for _, s := range tss {
+				if promrelabel.GetLabelByName(s.Labels, "__name__").Value == alertHistoryMetricName {
+					historySeries++
+				}
+			}
+			ts = ts.Add(time.Minute)
+		}
+		if persist && historySeries != 5 {
+			t.Fatalf("unexpected number of %s series; got %d; want %d", alertHistoryMetricName, historySeries, 5)
+		}
+		if !persist && historySeries != 0 {
+			t.Fatalf("unexpected %s series when persisting is disabled", alertHistoryMetricName)
+		}
+
+		transitions := ar.GetHistory()
+		if len(transitions) != len(transitionsExpected) {
+			t.Fatalf("unexpected number of transitions; got %d; want %d", len(transitions), len(transitionsExpected))
+		}
+		for i, got := range transitions {
+			exp := transitionsExpected[i]
+			if got.From != exp.From || got.To != exp.To || got.Value != exp.Value || got.Duration != exp.Duration {
+				t.Fatalf("unexpected transition #%d; got %s => %s (value %v, duration %s); want %s => %s (value %v, duration %s)",
+					i, got.From, got.To, got.Value, got.Duration, exp.From, exp.To, exp.Value, exp.Duration)
+			}
+		}
+	}
+
+	all := []AlertTransition{
+		{From: notifier.StateInactive, To: notifier.StatePending, Value: 1},
+		{From: notifier.StatePending, To: notifier.StateFiring, Value: 2, Duration: time.Minute},
+		{From: notifier.StateFiring, To: notifier.StateInactive, Value: 2, Duration: time.Minute},
+		{From: notifier.StateInactive, To: notifier.StatePending, Value: 3, Duration: time.Minute},
+		{From: notifier.StatePending, To: notifier.StateInactive, Value: 3, Duration: time.Minute},
+	}
+
+	// history is disabled
+	f(0, false, nil)
+
+	// history keeps all the transitions
+	f(10, false, all)
+
+	// history keeps only the most recent transitions
+	f(3, false, all[2:])
+
+	// transitions are persisted
+	f(0, true, nil)
+}
+
+func TestAlertingRule_HistoryLimitExceeded(t *testing.T) {
+	defer func(v bool) {
+		*alertHistoryPersist = v
+	}(*alertHistoryPersist)
+	*alertHistoryPersist = true
+
+	fq := &datasource.FakeQuerier{}
+	ar := newTestAlertingRule("test", 0)
+	ar.q = fq
+	ar.history = newAlertHistory(10)
+
+	fq.Add(metricWithValueAndLabels(t, 1, "instance", "foo"))
+	fq.Add(metricWithValueAndLabels(t, 1, "instance", "bar"))
+	tss, err := ar.exec(context.TODO(), time.Now(), 1)
+	if err == nil {
+		t.Fatalf("expecting non-nil error when the limit is exceeded")
+	}
+	if len(tss) != 0 {
+		t.Fatalf("unexpected time series returned when the limit is exceeded: %v", tss)
+	}
+	if transitions := ar.GetHistory(); len(transitions) != 0 {
+		t.Fatalf("unexpected transitions recorded when the limit is exceeded: %v", transitions)
+	}
+
+	// transitions are recorded once the alerts fit the limit
+	tss, err = ar.exec(context.TODO(), time.Now(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var historySeries int
+	for _, s := range tss {
+		if promrelabel.GetLabelByName(s.Labels, "__name__").Value == alertHistoryMetricName {
+			historySeries++
+		}
+	}
+	// each alert goes INACTIVE => PENDING => FIRING since the rule has no `for`
+	if historySeries != 4 {
+		t.Fatalf("unexpected number of %s series; got %d; want %d", alertHistoryMetricName, historySeries, 4)
+	}
+	if transitions := ar.GetHistory(); len(transitions) != 4 {
+		t.Fatalf("unexpected number of transitions; got %d; want %d", len(transitions), 4)
+	}
+}
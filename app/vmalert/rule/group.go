@@ -19,6 +19,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/datasource"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/remotewrite"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/silence"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/vmalertutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
@@ -49,6 +50,9 @@ type Group struct {
 	Type       config.Type
 	Interval   time.Duration
 	EvalOffset *time.Duration
+	// EvalJitter spreads evaluations of groups with the same EvalOffset
+	// over the time range [EvalOffset ... EvalOffset+EvalJitter].
+	EvalJitter *time.Duration
 	// EvalDelay will adjust timestamp for rule evaluation requests to compensate intentional query delay from datasource.
 	// see https://github.com/VictoriaMetrics/VictoriaMetrics/issues/5155
 	EvalDelay   *time.Duration
@@ -135,6 +139,9 @@ func NewGroup(cfg config.Group, qb datasource.QuerierBuilder, defaultInterval ti
 	if cfg.EvalOffset != nil {
 		g.EvalOffset = &cfg.EvalOffset.D
 	}
+	if cfg.EvalJitter != nil {
+		g.EvalJitter = &cfg.EvalJitter.D
+	}
 	if cfg.EvalDelay != nil {
 		g.EvalDelay = &cfg.EvalDelay.D
 	}
@@ -199,6 +206,9 @@ func (g *Group) CreateID() uint64 {
 	if g.EvalOffset != nil {
 		hash.Write([]byte(g.EvalOffset.String()))
 	}
+	if g.EvalJitter != nil {
+		hash.Write([]byte(g.EvalJitter.String()))
+	}
 	return hash.Sum64()
 }
 
@@ -333,7 +343,7 @@ func (g *Group) Start(ctx context.Context, nts func() []notifier.Notifier, rw re
 	// sleep random duration to spread group rules evaluation
 	// over time in order to reduce load on datasource.
 	if !SkipRandSleepOnGroupStart {
-		sleepBeforeStart := delayBeforeStart(evalTS, g.GetID(), g.Interval, g.EvalOffset)
+		sleepBeforeStart := delayBeforeStart(evalTS, g.GetID(), g.Interval, g.EvalOffset, g.EvalJitter)
 		g.infof("will start in %v", sleepBeforeStart)
 
 		sleepTimer := time.NewTimer(sleepBeforeStart)
@@ -486,9 +496,15 @@ func (g *Group) DeepCopy() *Group {
 
 // if offset is specified, delayBeforeStart returns a duration to help aligning timestamp with offset;
 // otherwise, it returns a random duration between [0..interval] based on group key.
-func delayBeforeStart(ts time.Time, key uint64, interval time.Duration, offset *time.Duration) time.Duration {
+func delayBeforeStart(ts time.Time, key uint64, interval time.Duration, offset, jitter *time.Duration) time.Duration {
 	if offset != nil {
-		currentOffsetPoint := ts.Truncate(interval).Add(*offset)
+		evalOffset := *offset
+		if jitter != nil {
+			// spread groups with the same offset over [offset ... offset+jitter]
+			// in order to reduce load spikes on datasource.
+			evalOffset += time.Duration(float64(*jitter) * (float64(key) / (1 << 64)))
+		}
+		currentOffsetPoint := ts.Truncate(interval).Add(evalOffset)
 		if currentOffsetPoint.Before(ts) {
 			// wait until the next offset point
 			return currentOffsetPoint.Add(interval).Sub(ts)
@@ -508,8 +524,8 @@ func delayBeforeStart(ts time.Time, key uint64, interval time.Duration, offset *
 
 func (g *Group) infof(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	logger.Infof("group %q %s; interval=%v; eval_offset=%v; concurrency=%d",
-		g.Name, msg, g.Interval, g.EvalOffset, g.Concurrency)
+	logger.Infof("group %q %s; interval=%v; eval_offset=%v; eval_jitter=%v; concurrency=%d",
+		g.Name, msg, g.Interval, g.EvalOffset, g.EvalJitter, g.Concurrency)
 }
 
 // Replay performs group replay
@@ -775,6 +791,9 @@ func (e *executor) exec(ctx context.Context, r Rule, ts time.Time, resolveDurati
 	}
 
 	alerts := ar.alertsToSend(resolveDuration, *resendDelay)
+	// Drop alerts matching active silences.
+	// See https://docs.victoriametrics.com/victoriametrics/vmalert/#silences
+	alerts = silence.Filter(alerts, time.Now())
 	if len(alerts) < 1 {
 		return nil
 	}
@@ -585,7 +585,7 @@ func TestGroupStartDelay(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		delay := delayBeforeStart(at, key, g.Interval, g.EvalOffset)
+		delay := delayBeforeStart(at, key, g.Interval, g.EvalOffset, g.EvalJitter)
 		gotStart := at.Add(delay)
 		if expTS != gotStart {
 			t.Fatalf("expected to get %v; got %v instead", expTS, gotStart)
@@ -606,6 +606,14 @@ func TestGroupStartDelay(t *testing.T) {
 	f("2023-01-01T00:01:00.000+00:00", "2023-01-01T00:03:00.000+00:00")
 	f("2023-01-01T00:03:30.000+00:00", "2023-01-01T00:08:00.000+00:00")
 	f("2023-01-01T00:08:00.000+00:00", "2023-01-01T00:08:00.000+00:00")
+
+	// test group with offset and jitter
+	jitter := time.Minute
+	g.EvalJitter = &jitter
+
+	f("2023-01-01T00:00:15.000+00:00", "2023-01-01T00:03:06.000+00:00")
+	f("2023-01-01T00:03:06.000+00:00", "2023-01-01T00:03:06.000+00:00")
+	f("2023-01-01T00:03:30.000+00:00", "2023-01-01T00:08:06.000+00:00")
 }
 
 func TestGetPrometheusReqTimestamp(t *testing.T) {
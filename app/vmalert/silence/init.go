@@ -0,0 +1,102 @@
+package silence
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
+)
+
+var (
+	storagePath = flag.String("silence.storagePath", "", "Optional path to the file for persisting silences created via /api/v1/silences API. "+
+		"Silences are kept in memory only and are lost on restart if the path isn't set. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmalert/#silences")
+	retention = flag.Duration("silence.retention", 5*24*time.Hour, "How long to keep expired silences before removing them. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmalert/#silences")
+)
+
+var (
+	// store is a global Store for silences.
+	// It is supposed to be inited via Init function only.
+	store = &Store{
+		silences: make(map[string]*Silence),
+	}
+
+	alertsSilenced = metrics.NewCounter(`vmalert_alerts_silenced_total`)
+	_              = metrics.NewGauge(`vmalert_silences_active`, func() float64 {
+		return float64(store.activeCount(time.Now()))
+	})
+)
+
+var (
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+)
+
+// Init loads silences from -silence.storagePath.
+//
+// Silences expired more than -silence.retention ago are periodically removed until Stop is called.
+func Init() error {
+	s, err := NewStore(*storagePath, *retention)
+	if err != nil {
+		return err
+	}
+	store = s
+
+	stopCh = make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runRetentionWatcher(s, stopCh)
+	}()
+	return nil
+}
+
+// Stop stops the background removal of expired silences started by Init.
+func Stop() {
+	close(stopCh)
+	wg.Wait()
+}
+
+func runRetentionWatcher(s *Store, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.removeExpired(time.Now())
+		}
+	}
+}
+
+// GetStore returns the global Store for silences.
+func GetStore() *Store {
+	return store
+}
+
+// Filter returns alerts, which aren't matched by silences active at ts.
+//
+// Resolved alerts are always returned, so notifiers receive resolve notifications for alerts,
+// which were firing before the silence creation.
+// Alerts are matched by their labels before applying `alert_relabel_configs` from -notifier.config.
+func Filter(alerts []notifier.Alert, ts time.Time) []notifier.Alert {
+	s := store
+	if s.isEmpty() {
+		return alerts
+	}
+	result := alerts[:0:0]
+	for _, a := range alerts {
+		if a.State != notifier.StateInactive && s.IsSilenced(a.Labels, ts) {
+			alertsSilenced.Inc()
+			continue
+		}
+		result = append(result, a)
+	}
+	return result
+}
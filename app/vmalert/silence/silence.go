@@ -0,0 +1,347 @@
+package silence
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/regexutil"
+)
+
+var (
+	// ErrNotFound is returned when the silence with the given id doesn't exist.
+	ErrNotFound = errors.New("silence not found")
+	// ErrExpired is returned when expiring already expired silence.
+	ErrExpired = errors.New("silence is already expired")
+)
+
+// Matcher defines a matcher for alert labels.
+//
+// The format is compatible with Alertmanager API v2 silence matchers.
+type Matcher struct {
+	// Name is the label name to match
+	Name string `json:"name"`
+	// Value is the label value or regular expression to match
+	Value string `json:"value"`
+	// IsRegex defines whether Value is a regular expression
+	IsRegex bool `json:"isRegex"`
+	// IsEqual defines whether the matcher is positive (= or =~) or negative (!= or !~).
+	// It is set to true by default.
+	IsEqual *bool `json:"isEqual,omitempty"`
+
+	re *regexutil.PromRegex
+}
+
+func (m *Matcher) init() error {
+	if m.Name == "" {
+		return fmt.Errorf("matcher name cannot be empty")
+	}
+	if !m.IsRegex {
+		return nil
+	}
+	re, err := regexutil.NewPromRegex(m.Value)
+	if err != nil {
+		return fmt.Errorf("cannot parse regex %q for matcher %q: %w", m.Value, m.Name, err)
+	}
+	m.re = re
+	return nil
+}
+
+func (m *Matcher) isEqual() bool {
+	return m.IsEqual == nil || *m.IsEqual
+}
+
+func (m *Matcher) match(labels map[string]string) bool {
+	v := labels[m.Name]
+	var ok bool
+	if m.re != nil {
+		ok = m.re.MatchString(v)
+	} else {
+		ok = v == m.Value
+	}
+	return ok == m.isEqual()
+}
+
+// String returns human-readable representation of m.
+func (m *Matcher) String() string {
+	var op string
+	switch {
+	case m.isEqual() && !m.IsRegex:
+		op = "="
+	case m.isEqual() && m.IsRegex:
+		op = "=~"
+	case !m.isEqual() && !m.IsRegex:
+		op = "!="
+	default:
+		op = "!~"
+	}
+	return fmt.Sprintf("%s%s%q", m.Name, op, m.Value)
+}
+
+// Silence suppresses notifications for alerts matching all the Matchers
+// during the time range [StartsAt ... EndsAt].
+type Silence struct {
+	// ID is the unique identifier of the silence
+	ID string `json:"id"`
+	// Matchers is the list of label matchers. All of them must match alert labels.
+	Matchers []*Matcher `json:"matchers"`
+	// StartsAt is the time when the silence becomes active
+	StartsAt time.Time `json:"startsAt"`
+	// EndsAt is the time when the silence expires
+	EndsAt time.Time `json:"endsAt"`
+	// CreatedBy is an optional author of the silence
+	CreatedBy string `json:"createdBy,omitempty"`
+	// Comment is an optional comment for the silence
+	Comment string `json:"comment,omitempty"`
+	// UpdatedAt is the time of the last silence update
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate checks s for correctness and initializes its matchers.
+func (s *Silence) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("silence must contain at least a single matcher")
+	}
+	for _, m := range s.Matchers {
+		if err := m.init(); err != nil {
+			return err
+		}
+	}
+	if s.EndsAt.IsZero() {
+		return fmt.Errorf("missing endsAt")
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("endsAt=%s must be bigger than startsAt=%s", s.EndsAt.Format(time.RFC3339), s.StartsAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// IsActive returns true if s is active at the given ts.
+func (s *Silence) IsActive(ts time.Time) bool {
+	return !ts.Before(s.StartsAt) && ts.Before(s.EndsAt)
+}
+
+// State returns the state of s at the given ts: pending, active or expired.
+func (s *Silence) State(ts time.Time) string {
+	if ts.Before(s.StartsAt) {
+		return "pending"
+	}
+	if ts.Before(s.EndsAt) {
+		return "active"
+	}
+	return "expired"
+}
+
+// Matches returns true if all the matchers in s match the given labels.
+func (s *Silence) Matches(labels map[string]string) bool {
+	for _, m := range s.Matchers {
+		if !m.match(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Store holds silences and persists them to the file at path.
+//
+// Store is safe for concurrent use.
+type Store struct {
+	// path is an optional path to the file for persisting silences
+	path string
+	// retention is the duration for keeping expired silences
+	retention time.Duration
+
+	mu       sync.RWMutex
+	silences map[string]*Silence
+}
+
+// NewStore returns new Store, which persists silences to the given path.
+//
+// Silences are kept in memory only if path is empty.
+// Expired silences are removed after the given retention.
+func NewStore(path string, retention time.Duration) (*Store, error) {
+	s := &Store{
+		path:      path,
+		retention: retention,
+		silences:  make(map[string]*Silence),
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("cannot read silences from %q: %w", path, err)
+	}
+	var silences []*Silence
+	if err := json.Unmarshal(data, &silences); err != nil {
+		return nil, fmt.Errorf("cannot parse silences from %q: %w", path, err)
+	}
+	for _, sl := range silences {
+		if err := sl.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid silence %q in %q: %w", sl.ID, path, err)
+		}
+		s.silences[sl.ID] = sl
+	}
+	return s, nil
+}
+
+// Upsert validates and adds sl to s.
+//
+// The silence with the same ID is replaced by sl.
+// New ID is generated for sl if it is empty.
+func (s *Store) Upsert(sl *Silence) error {
+	now := time.Now()
+	if sl.StartsAt.IsZero() {
+		sl.StartsAt = now
+	}
+	if err := sl.Validate(); err != nil {
+		return err
+	}
+	sl.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sl.ID == "" {
+		sl.ID = rand.Text()
+	} else if _, ok := s.silences[sl.ID]; !ok {
+		return fmt.Errorf("cannot update silence with id=%q: %w", sl.ID, ErrNotFound)
+	}
+	s.silences[sl.ID] = sl
+	s.mustPersistLocked(now)
+	return nil
+}
+
+// Expire expires the silence with the given id.
+func (s *Store) Expire(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl, ok := s.silences[id]
+	if !ok {
+		return fmt.Errorf("cannot expire silence with id=%q: %w", id, ErrNotFound)
+	}
+	now := time.Now()
+	if !sl.EndsAt.After(now) {
+		return fmt.Errorf("cannot expire silence with id=%q: %w", id, ErrExpired)
+	}
+	slCopy := *sl
+	slCopy.EndsAt = now
+	if slCopy.StartsAt.After(now) {
+		slCopy.StartsAt = now
+	}
+	slCopy.UpdatedAt = now
+	s.silences[id] = &slCopy
+	s.mustPersistLocked(now)
+	return nil
+}
+
+// Get returns the silence with the given id.
+func (s *Store) Get(id string) (*Silence, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sl, ok := s.silences[id]
+	return sl, ok
+}
+
+// List returns all the silences sorted by EndsAt.
+func (s *Store) List() []*Silence {
+	s.mu.RLock()
+	silences := make([]*Silence, 0, len(s.silences))
+	for _, sl := range s.silences {
+		silences = append(silences, sl)
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(silences, func(a, b *Silence) int {
+		if n := a.EndsAt.Compare(b.EndsAt); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return silences
+}
+
+// IsSilenced returns true if the given labels are matched by any silence active at ts.
+func (s *Store) IsSilenced(labels map[string]string, ts time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sl := range s.silences {
+		if sl.IsActive(ts) && sl.Matches(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeExpired removes silences expired more than s.retention ago at the given ts.
+func (s *Store) removeExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.removeExpiredLocked(now) > 0 {
+		s.mustPersistLocked(now)
+	}
+}
+
+// removeExpiredLocked removes silences expired more than s.retention ago and returns the number of removed silences.
+func (s *Store) removeExpiredLocked(now time.Time) int {
+	n := 0
+	for id, sl := range s.silences {
+		if now.Sub(sl.EndsAt) > s.retention {
+			delete(s.silences, id)
+			n++
+		}
+	}
+	return n
+}
+
+// mustPersistLocked removes silences expired more than s.retention ago and writes the remaining silences to s.path.
+func (s *Store) mustPersistLocked(now time.Time) {
+	s.removeExpiredLocked(now)
+	if s.path == "" {
+		return
+	}
+	silences := make([]*Silence, 0, len(s.silences))
+	for _, sl := range s.silences {
+		silences = append(silences, sl)
+	}
+	data, err := json.Marshal(silences)
+	if err != nil {
+		logger.Panicf("BUG: cannot marshal silences: %s", err)
+	}
+	fs.MustWriteAtomic(s.path, data, true)
+}
+
+func (s *Store) isEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.silences) == 0
+}
+
+func (s *Store) activeCount(ts time.Time) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := 0
+	for _, sl := range s.silences {
+		if sl.IsActive(ts) {
+			n++
+		}
+	}
+	return n
+}
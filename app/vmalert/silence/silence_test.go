@@ -0,0 +1,262 @@
+package silence
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
+)
+
+func TestSilenceMatches(t *testing.T) {
+	f := func(matchers []*Matcher, labels map[string]string, resultExpected bool) {
+		t.Helper()
+
+		sl := &Silence{
+			Matchers: matchers,
+			EndsAt:   time.Now().Add(time.Hour),
+		}
+		if err := sl.Validate(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		result := sl.Matches(labels)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for labels %v; got %v; want %v", labels, result, resultExpected)
+		}
+	}
+
+	notEqual := false
+	labels := map[string]string{
+		"alertname": "HostDown",
+		"instance":  "host-1:9100",
+		"job":       "node",
+	}
+
+	// equal matcher
+	f([]*Matcher{{Name: "alertname", Value: "HostDown"}}, labels, true)
+	f([]*Matcher{{Name: "alertname", Value: "HostUp"}}, labels, false)
+
+	// not equal matcher
+	f([]*Matcher{{Name: "alertname", Value: "HostUp", IsEqual: &notEqual}}, labels, true)
+	f([]*Matcher{{Name: "alertname", Value: "HostDown", IsEqual: &notEqual}}, labels, false)
+
+	// regex matcher
+	f([]*Matcher{{Name: "instance", Value: "host-.+", IsRegex: true}}, labels, true)
+	f([]*Matcher{{Name: "instance", Value: "host", IsRegex: true}}, labels, false)
+	f([]*Matcher{{Name: "instance", Value: "host-.+", IsRegex: true, IsEqual: &notEqual}}, labels, false)
+
+	// missing label
+	f([]*Matcher{{Name: "env", Value: ""}}, labels, true)
+	f([]*Matcher{{Name: "env", Value: "prod"}}, labels, false)
+
+	// all the matchers must match
+	f([]*Matcher{
+		{Name: "alertname", Value: "HostDown"},
+		{Name: "job", Value: "node"},
+	}, labels, true)
+	f([]*Matcher{
+		{Name: "alertname", Value: "HostDown"},
+		{Name: "job", Value: "vmagent"},
+	}, labels, false)
+}
+
+func TestSilenceValidateFailure(t *testing.T) {
+	f := func(sl *Silence) {
+		t.Helper()
+
+		if err := sl.Validate(); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	now := time.Now()
+
+	// missing matchers
+	f(&Silence{EndsAt: now})
+
+	// empty matcher name
+	f(&Silence{
+		Matchers: []*Matcher{{Value: "foo"}},
+		EndsAt:   now,
+	})
+
+	// invalid regex
+	f(&Silence{
+		Matchers: []*Matcher{{Name: "foo", Value: "[", IsRegex: true}},
+		EndsAt:   now,
+	})
+
+	// missing endsAt
+	f(&Silence{
+		Matchers: []*Matcher{{Name: "foo", Value: "bar"}},
+	})
+
+	// endsAt before startsAt
+	f(&Silence{
+		Matchers: []*Matcher{{Name: "foo", Value: "bar"}},
+		StartsAt: now,
+		EndsAt:   now.Add(-time.Minute),
+	})
+}
+
+func TestSilenceState(t *testing.T) {
+	now := time.Now()
+	sl := &Silence{
+		StartsAt: now,
+		EndsAt:   now.Add(time.Hour),
+	}
+	if s := sl.State(now.Add(-time.Minute)); s != "pending" {
+		t.Fatalf("unexpected state; got %q; want %q", s, "pending")
+	}
+	if s := sl.State(now.Add(time.Minute)); s != "active" {
+		t.Fatalf("unexpected state; got %q; want %q", s, "active")
+	}
+	if s := sl.State(now.Add(time.Hour)); s != "expired" {
+		t.Fatalf("unexpected state; got %q; want %q", s, "expired")
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences.json")
+	s, err := NewStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("cannot create store: %s", err)
+	}
+
+	labels := map[string]string{"alertname": "HostDown"}
+	now := time.Now()
+	if s.IsSilenced(labels, now) {
+		t.Fatalf("unexpected silenced alert in empty store")
+	}
+
+	sl := &Silence{
+		Matchers: []*Matcher{{Name: "alertname", Value: "HostDown"}},
+		EndsAt:   now.Add(time.Hour),
+		Comment:  "maintenance",
+	}
+	if err := s.Upsert(sl); err != nil {
+		t.Fatalf("cannot add silence: %s", err)
+	}
+	if sl.ID == "" {
+		t.Fatalf("expecting non-empty silence id")
+	}
+	if !s.IsSilenced(labels, time.Now()) {
+		t.Fatalf("expecting alert to be silenced")
+	}
+
+	// update of unknown silence must fail
+	if err := s.Upsert(&Silence{
+		ID:       "unknown",
+		Matchers: []*Matcher{{Name: "alertname", Value: "HostDown"}},
+		EndsAt:   now.Add(time.Hour),
+	}); err == nil {
+		t.Fatalf("expecting non-nil error when updating unknown silence")
+	}
+
+	// silence with missing startsAt and endsAt in the past must fail
+	slPast := &Silence{
+		Matchers: []*Matcher{{Name: "alertname", Value: "HostDown"}},
+		EndsAt:   now.Add(-time.Hour),
+	}
+	if err := s.Upsert(slPast); err == nil {
+		t.Fatalf("expecting non-nil error when adding silence with endsAt in the past")
+	}
+	if slPast.ID != "" {
+		t.Fatalf("unexpected id for rejected silence: %q", slPast.ID)
+	}
+	if n := len(s.List()); n != 1 {
+		t.Fatalf("unexpected number of silences; got %d; want 1", n)
+	}
+
+	// silences must be restored from the file
+	s2, err := NewStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("cannot load store: %s", err)
+	}
+	sl2, ok := s2.Get(sl.ID)
+	if !ok {
+		t.Fatalf("cannot find silence %q after reload", sl.ID)
+	}
+	if sl2.Comment != sl.Comment {
+		t.Fatalf("unexpected comment; got %q; want %q", sl2.Comment, sl.Comment)
+	}
+	if !s2.IsSilenced(labels, time.Now()) {
+		t.Fatalf("expecting alert to be silenced after reload")
+	}
+
+	// expired silence must stop silencing alerts
+	if err := s2.Expire(sl.ID); err != nil {
+		t.Fatalf("cannot expire silence: %s", err)
+	}
+	if s2.IsSilenced(labels, time.Now().Add(time.Second)) {
+		t.Fatalf("unexpected silenced alert after expiration")
+	}
+	if err := s2.Expire(sl.ID); !errors.Is(err, ErrExpired) {
+		t.Fatalf("unexpected error when expiring already expired silence; got %v; want %v", err, ErrExpired)
+	}
+	if err := s2.Expire("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error when expiring unknown silence; got %v; want %v", err, ErrNotFound)
+	}
+	if n := len(s2.List()); n != 1 {
+		t.Fatalf("unexpected number of silences; got %d; want 1", n)
+	}
+
+	// expired silence must be kept during the retention
+	s2.removeExpired(time.Now().Add(30 * time.Minute))
+	if n := len(s2.List()); n != 1 {
+		t.Fatalf("unexpected number of silences during the retention; got %d; want 1", n)
+	}
+
+	// expired silence must be removed after the retention without other updates
+	s2.removeExpired(time.Now().Add(2 * time.Hour))
+	if n := len(s2.List()); n != 0 {
+		t.Fatalf("unexpected number of silences after the retention; got %d; want 0", n)
+	}
+	s3, err := NewStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("cannot load store: %s", err)
+	}
+	if n := len(s3.List()); n != 0 {
+		t.Fatalf("unexpected number of persisted silences after the retention; got %d; want 0", n)
+	}
+}
+
+func TestFilterResolvedAlerts(t *testing.T) {
+	storeOrig := store
+	defer func() { store = storeOrig }()
+
+	s, err := NewStore("", time.Hour)
+	if err != nil {
+		t.Fatalf("cannot create store: %s", err)
+	}
+	store = s
+
+	labels := map[string]string{"alertname": "HostDown"}
+	now := time.Now()
+
+	// the alert fires before the silence is created
+	alerts := Filter([]notifier.Alert{{Labels: labels, State: notifier.StateFiring}}, now)
+	if len(alerts) != 1 {
+		t.Fatalf("unexpected number of alerts before the silence creation; got %d; want 1", len(alerts))
+	}
+
+	if err := s.Upsert(&Silence{
+		Matchers: []*Matcher{{Name: "alertname", Value: "HostDown"}},
+		EndsAt:   now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("cannot add silence: %s", err)
+	}
+
+	// the firing alert must be silenced
+	alerts = Filter([]notifier.Alert{{Labels: labels, State: notifier.StateFiring}}, time.Now())
+	if len(alerts) != 0 {
+		t.Fatalf("unexpected number of firing alerts after the silence creation; got %d; want 0", len(alerts))
+	}
+
+	// the resolve notification must be sent despite the active silence
+	alerts = Filter([]notifier.Alert{{Labels: labels, State: notifier.StateInactive}}, time.Now())
+	if len(alerts) != 1 {
+		t.Fatalf("unexpected number of resolved alerts after the silence creation; got %d; want 1", len(alerts))
+	}
+}
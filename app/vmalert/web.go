@@ -3,15 +3,18 @@ package main
 import (
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/config"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/rule"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/silence"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/tpl"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
@@ -20,7 +23,11 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 )
 
-var reloadAuthKey = flagutil.NewPassword("reloadAuthKey", "Auth key for /-/reload http endpoint. It must be passed via authKey query arg. It overrides -httpAuth.*")
+var (
+	reloadAuthKey  = flagutil.NewPassword("reloadAuthKey", "Auth key for /-/reload http endpoint. It must be passed via authKey query arg. It overrides -httpAuth.*")
+	silenceAuthKey = flagutil.NewPassword("silence.authKey", "Auth key for creating, updating and expiring silences via /api/v1/silences and /api/v1/silence http endpoints. "+
+		"It must be passed via authKey query arg. It overrides -httpAuth.* . See https://docs.victoriametrics.com/victoriametrics/vmalert/#silences")
+)
 
 var (
 	apiLinks = [][2]string{
@@ -30,6 +37,8 @@ var (
 		{"api/v1/alerts", "list all active alerts"},
 		{"api/v1/notifiers", "list all notifiers"},
 		{fmt.Sprintf("api/v1/alert?%s=<int>&%s=<int>", paramGroupID, paramAlertID), "get alert status by group and alert ID"},
+		{"api/v1/alerts/history", "list recent alert state transitions"},
+		{"api/v1/silences", "list all silences"},
 	}
 	systemLinks = [][2]string{
 		{"vmalert/groups", "UI"},
@@ -163,6 +172,15 @@ func (rh *requestHandler) handler(w http.ResponseWriter, r *http.Request) bool {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(data)
 		return true
+	case "/vmalert/api/v1/alerts/history", "/api/v1/alerts/history":
+		data, err := rh.listAlertsHistory(r)
+		if err != nil {
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return true
 	case "/vmalert/api/v1/alert", "/api/v1/alert":
 		alert, err := rh.getAlert(r)
 		if err != nil {
@@ -195,6 +213,48 @@ func (rh *requestHandler) handler(w http.ResponseWriter, r *http.Request) bool {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(data)
 		return true
+	case "/vmalert/api/v1/silences", "/api/v1/silences":
+		var data []byte
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			data, err = listSilences()
+		case http.MethodPost:
+			if !httpserver.CheckAuthFlag(w, r, silenceAuthKey) {
+				return true
+			}
+			data, err = upsertSilence(r)
+		default:
+			err = errResponse(fmt.Errorf("path %q supports only GET and POST methods", r.URL.Path), http.StatusMethodNotAllowed)
+		}
+		if err != nil {
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return true
+	case "/vmalert/api/v1/silence", "/api/v1/silence":
+		var data []byte
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			data, err = getSilence(r)
+		case http.MethodDelete:
+			if !httpserver.CheckAuthFlag(w, r, silenceAuthKey) {
+				return true
+			}
+			data, err = expireSilence(r)
+		default:
+			err = errResponse(fmt.Errorf("path %q supports only GET and DELETE methods", r.URL.Path), http.StatusMethodNotAllowed)
+		}
+		if err != nil {
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return true
 	case "/-/reload":
 		if !httpserver.CheckAuthFlag(w, r, reloadAuthKey) {
 			return true
@@ -440,6 +500,70 @@ func (rh *requestHandler) listAlerts(rf *rulesFilter) ([]byte, error) {
 	return b, nil
 }
 
+type listAlertsHistoryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Transitions []*apiAlertTransition `json:"transitions"`
+	} `json:"data"`
+}
+
+// listAlertsHistory returns recent alert state transitions.
+// The list can be narrowed down to the specific group and rule via group_id and rule_id params.
+func (rh *requestHandler) listAlertsHistory(r *http.Request) ([]byte, error) {
+	var groupID, ruleID uint64
+	var err error
+	if s := r.FormValue(paramGroupID); s != "" {
+		groupID, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, errResponse(fmt.Errorf("failed to read %q param: %w", paramGroupID, err), http.StatusBadRequest)
+		}
+	}
+	if s := r.FormValue(paramRuleID); s != "" {
+		ruleID, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, errResponse(fmt.Errorf("failed to read %q param: %w", paramRuleID, err), http.StatusBadRequest)
+		}
+	}
+
+	rh.m.groupsMu.RLock()
+	defer rh.m.groupsMu.RUnlock()
+
+	lr := listAlertsHistoryResponse{Status: "success"}
+	lr.Data.Transitions = make([]*apiAlertTransition, 0)
+	for _, group := range rh.m.groups {
+		if groupID != 0 && group.GetID() != groupID {
+			continue
+		}
+		for _, r := range group.Rules {
+			a, ok := r.(*rule.AlertingRule)
+			if !ok {
+				continue
+			}
+			if ruleID != 0 && a.RuleID != ruleID {
+				continue
+			}
+			lr.Data.Transitions = append(lr.Data.Transitions, ruleToAPIAlertTransitions(a)...)
+		}
+	}
+
+	// sort list of transitions for deterministic output
+	slices.SortFunc(lr.Data.Transitions, func(a, b *apiAlertTransition) int {
+		if n := a.Time.Compare(b.Time); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	b, err := json.Marshal(lr)
+	if err != nil {
+		return nil, &httpserver.ErrorWithStatusCode{
+			Err:        fmt.Errorf(`error encoding list of alert state transitions: %w`, err),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+	return b, nil
+}
+
 type listNotifiersResponse struct {
 	Status string `json:"status"`
 	Data   struct {
@@ -476,6 +600,79 @@ func (rh *requestHandler) listNotifiers() ([]byte, error) {
 	return b, nil
 }
 
+type listSilencesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Silences []*apiSilence `json:"silences"`
+	} `json:"data"`
+}
+
+func listSilences() ([]byte, error) {
+	now := time.Now()
+	lr := listSilencesResponse{Status: "success"}
+	lr.Data.Silences = make([]*apiSilence, 0)
+	for _, sl := range silence.GetStore().List() {
+		lr.Data.Silences = append(lr.Data.Silences, newAPISilence(sl, now))
+	}
+	b, err := json.Marshal(lr)
+	if err != nil {
+		return nil, errResponse(fmt.Errorf(`error encoding list of silences: %w`, err), http.StatusInternalServerError)
+	}
+	return b, nil
+}
+
+type upsertSilenceResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		SilenceID string `json:"silenceID"`
+	} `json:"data"`
+}
+
+func upsertSilence(r *http.Request) ([]byte, error) {
+	var sl silence.Silence
+	if err := json.NewDecoder(r.Body).Decode(&sl); err != nil {
+		return nil, errResponse(fmt.Errorf("cannot parse silence: %w", err), http.StatusBadRequest)
+	}
+	if err := silence.GetStore().Upsert(&sl); err != nil {
+		return nil, errResponse(fmt.Errorf("cannot save silence: %w", err), http.StatusBadRequest)
+	}
+	ur := upsertSilenceResponse{Status: "success"}
+	ur.Data.SilenceID = sl.ID
+	b, err := json.Marshal(ur)
+	if err != nil {
+		return nil, errResponse(fmt.Errorf(`error encoding silence id: %w`, err), http.StatusInternalServerError)
+	}
+	return b, nil
+}
+
+func getSilence(r *http.Request) ([]byte, error) {
+	id := r.FormValue(paramSilenceID)
+	sl, ok := silence.GetStore().Get(id)
+	if !ok {
+		return nil, errResponse(fmt.Errorf("cannot find silence with %s=%q", paramSilenceID, id), http.StatusNotFound)
+	}
+	b, err := json.Marshal(newAPISilence(sl, time.Now()))
+	if err != nil {
+		return nil, errResponse(fmt.Errorf(`error encoding silence: %w`, err), http.StatusInternalServerError)
+	}
+	return b, nil
+}
+
+func expireSilence(r *http.Request) ([]byte, error) {
+	id := r.FormValue(paramSilenceID)
+	if err := silence.GetStore().Expire(id); err != nil {
+		sc := http.StatusBadRequest
+		switch {
+		case errors.Is(err, silence.ErrNotFound):
+			sc = http.StatusNotFound
+		case errors.Is(err, silence.ErrExpired):
+			sc = http.StatusConflict
+		}
+		return nil, errResponse(err, sc)
+	}
+	return []byte(`{"status":"success"}`), nil
+}
+
 func errResponse(err error, sc int) *httpserver.ErrorWithStatusCode {
 	return &httpserver.ErrorWithStatusCode{
 		Err:        err,
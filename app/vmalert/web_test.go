@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -123,6 +124,98 @@ func TestHandler(t *testing.T) {
 			t.Fatalf("expected 2 alert got %d", length)
 		}
 	})
+
+	t.Run("/api/v1/alerts/history", func(t *testing.T) {
+		lr := listAlertsHistoryResponse{}
+		getResp(t, ts.URL+"/api/v1/alerts/history", &lr, 200)
+		if lr.Data.Transitions == nil {
+			t.Fatalf("expected /api/v1/alerts/history response to have non-nil data")
+		}
+
+		lr = listAlertsHistoryResponse{}
+		getResp(t, ts.URL+"/vmalert/api/v1/alerts/history?"+fmt.Sprintf("%s=%d&%s=%d", paramGroupID, ar.GroupID, paramRuleID, ar.RuleID), &lr, 200)
+
+		getResp(t, ts.URL+"/api/v1/alerts/history?"+paramGroupID+"=foo", nil, 400)
+	})
+	t.Run("/api/v1/silences", func(t *testing.T) {
+		doReq := func(t *testing.T, method, url, body string, to any, code int) {
+			t.Helper()
+			req, err := http.NewRequest(method, url, strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("unexpected err %s", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected err %s", err)
+			}
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					t.Fatalf("err closing body %s", err)
+				}
+			}()
+			if code != resp.StatusCode {
+				t.Fatalf("unexpected status code %d want %d", resp.StatusCode, code)
+			}
+			if to != nil {
+				if err = json.NewDecoder(resp.Body).Decode(to); err != nil {
+					t.Fatalf("unexpected err %s", err)
+				}
+			}
+		}
+		body := fmt.Sprintf(`{"matchers":[{"name":"alertname","value":"web_test_silence"}],"endsAt":%q,"comment":"test"}`,
+			time.Now().Add(time.Hour).Format(time.RFC3339))
+
+		// mutating requests must be rejected without valid authKey
+		if err := silenceAuthKey.Set("secret"); err != nil {
+			t.Fatalf("cannot set -silence.authKey: %s", err)
+		}
+		defer func() {
+			if err := silenceAuthKey.Set(""); err != nil {
+				t.Fatalf("cannot reset -silence.authKey: %s", err)
+			}
+		}()
+		doReq(t, http.MethodPost, ts.URL+"/api/v1/silences", body, nil, 401)
+		doReq(t, http.MethodPost, ts.URL+"/api/v1/silences?authKey=foo", body, nil, 401)
+
+		// invalid silence
+		doReq(t, http.MethodPost, ts.URL+"/api/v1/silences?authKey=secret", `{"matchers":[]}`, nil, 400)
+		doReq(t, http.MethodPut, ts.URL+"/api/v1/silences?authKey=secret", body, nil, 405)
+
+		ur := upsertSilenceResponse{}
+		doReq(t, http.MethodPost, ts.URL+"/vmalert/api/v1/silences?authKey=secret", body, &ur, 200)
+		if ur.Data.SilenceID == "" {
+			t.Fatalf("expecting non-empty silence id")
+		}
+
+		// listing and reading silences doesn't require authKey
+		lr := listSilencesResponse{}
+		getResp(t, ts.URL+"/api/v1/silences", &lr, 200)
+		found := false
+		for _, sl := range lr.Data.Silences {
+			if sl.ID == ur.Data.SilenceID {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("cannot find silence %q in /api/v1/silences response", ur.Data.SilenceID)
+		}
+		sl := apiSilence{}
+		getResp(t, ts.URL+"/api/v1/silence?"+paramSilenceID+"="+ur.Data.SilenceID, &sl, 200)
+		if sl.State != "active" {
+			t.Fatalf("unexpected silence state; got %q; want %q", sl.State, "active")
+		}
+		getResp(t, ts.URL+"/api/v1/silence?"+paramSilenceID+"=unknown", nil, 404)
+
+		doReq(t, http.MethodDelete, ts.URL+"/api/v1/silence?"+paramSilenceID+"="+ur.Data.SilenceID, "", nil, 401)
+		doReq(t, http.MethodDelete, ts.URL+"/api/v1/silence?authKey=secret&"+paramSilenceID+"="+ur.Data.SilenceID, "", nil, 200)
+		doReq(t, http.MethodDelete, ts.URL+"/api/v1/silence?authKey=secret&"+paramSilenceID+"="+ur.Data.SilenceID, "", nil, 409)
+		doReq(t, http.MethodDelete, ts.URL+"/api/v1/silence?authKey=secret&"+paramSilenceID+"=unknown", "", nil, 404)
+		sl = apiSilence{}
+		getResp(t, ts.URL+"/api/v1/silence?"+paramSilenceID+"="+ur.Data.SilenceID, &sl, 200)
+		if sl.State != "expired" {
+			t.Fatalf("unexpected silence state; got %q; want %q", sl.State, "expired")
+		}
+	})
 	t.Run("/api/v1/alert?alertID&groupID", func(t *testing.T) {
 		expAlert := newAlertAPI(ar, ar.GetAlerts()[0])
 		alert := &apiAlert{}
@@ -304,6 +397,14 @@ func TestEmptyResponse(t *testing.T) {
 		}
 	})
 
+	t.Run("no groups /api/v1/alerts/history", func(t *testing.T) {
+		lr := listAlertsHistoryResponse{}
+		getResp(t, ts.URL+"/api/v1/alerts/history", &lr, 200)
+		if lr.Data.Transitions == nil {
+			t.Fatalf("expected /api/v1/alerts/history response to have non-nil data")
+		}
+	})
+
 	t.Run("no groups /api/v1/rules", func(t *testing.T) {
 		lr := listGroupsResponse{}
 		getResp(t, ts.URL+"/api/v1/rules", &lr, 200)
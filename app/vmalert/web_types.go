@@ -9,6 +9,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/rule"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/silence"
 )
 
 const (
@@ -18,6 +19,8 @@ const (
 	paramAlertID = "alert_id"
 	// ParamRuleID is rule id key in url parameter
 	paramRuleID = "rule_id"
+	// paramSilenceID is silence id key in url parameter
+	paramSilenceID = "id"
 )
 
 type apiNotifier struct {
@@ -30,6 +33,20 @@ type apiTarget struct {
 	Labels  map[string]string `json:"labels"`
 }
 
+// apiSilence represents silence.Silence with its current state
+type apiSilence struct {
+	*silence.Silence
+	// State is one of pending, active or expired
+	State string `json:"state"`
+}
+
+func newAPISilence(sl *silence.Silence, ts time.Time) *apiSilence {
+	return &apiSilence{
+		Silence: sl,
+		State:   sl.State(ts),
+	}
+}
+
 // apiAlert represents a notifier.AlertingRule state
 // for WEB view
 // https://github.com/prometheus/compliance/blob/main/alert_generator/specification.md#get-apiv1rules
@@ -106,6 +123,8 @@ type apiGroup struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// EvalOffset Group will be evaluated at the exact time offset on the range of [0...evaluationInterval]
 	EvalOffset float64 `json:"eval_offset,omitempty"`
+	// EvalJitter spreads Group evaluations over the range of [eval_offset...eval_offset+eval_jitter]
+	EvalJitter float64 `json:"eval_jitter,omitempty"`
 	// EvalDelay will adjust the `time` parameter of rule evaluation requests to compensate intentional query delay from datasource.
 	EvalDelay float64 `json:"eval_delay,omitempty"`
 	// Unhealthy unhealthy rules count
@@ -136,9 +155,11 @@ type apiRule struct {
 	// Duration represents Rule's `for` field
 	Duration float64 `json:"duration"`
 	// Alert will continue firing for this long even when the alerting expression no longer has results.
-	KeepFiringFor float64           `json:"keep_firing_for"`
-	Labels        map[string]string `json:"labels,omitempty"`
-	Annotations   map[string]string `json:"annotations,omitempty"`
+	KeepFiringFor float64 `json:"keep_firing_for"`
+	// Absent is set if the alerting rule fires when its expression returns no results.
+	Absent      bool              `json:"absent,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 	// LastError contains the error faced while executing the rule.
 	LastError string `json:"lastError"`
 	// EvaluationTime is the time taken to completely evaluate the rule in float seconds.
@@ -253,6 +274,7 @@ func alertingToAPI(ar *rule.AlertingRule) apiRule {
 		Query:             ar.Expr,
 		Duration:          ar.For.Seconds(),
 		KeepFiringFor:     ar.KeepFiringFor.Seconds(),
+		Absent:            ar.Absent,
 		Labels:            ar.Labels,
 		Annotations:       ar.Annotations,
 		LastEvaluation:    lastState.Time,
@@ -337,6 +359,51 @@ func newAlertAPI(ar *rule.AlertingRule, a *notifier.Alert) *apiAlert {
 	return aa
 }
 
+// apiAlertTransition represents rule.AlertTransition
+// for WEB view
+type apiAlertTransition struct {
+	// Time is the moment of time when transition happened
+	Time time.Time `json:"time"`
+	// ID is an unique Alert's ID within a group
+	ID string `json:"id"`
+	// RuleID is an unique Rule's ID within a group
+	RuleID string `json:"rule_id"`
+	// GroupID is an unique Group's ID
+	GroupID string `json:"group_id"`
+	// Name is the name of the alerting rule
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// From is the alert state before the transition
+	From string `json:"from"`
+	// To is the alert state after the transition
+	To string `json:"to"`
+	// Value is the alert value at the moment of transition
+	Value string `json:"value"`
+	// DurationSeconds is the number of seconds alert spent in From state
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+func ruleToAPIAlertTransitions(ar *rule.AlertingRule) []*apiAlertTransition {
+	var transitions []*apiAlertTransition
+	for _, at := range ar.GetHistory() {
+		transitions = append(transitions, &apiAlertTransition{
+			Time: at.Time,
+			// encode as strings to avoid rounding
+			ID:      fmt.Sprintf("%d", at.AlertID),
+			GroupID: fmt.Sprintf("%d", ar.GroupID),
+			RuleID:  fmt.Sprintf("%d", ar.RuleID),
+
+			Name:            ar.Name,
+			Labels:          at.Labels,
+			From:            at.From.String(),
+			To:              at.To.String(),
+			Value:           strconv.FormatFloat(at.Value, 'f', -1, 32),
+			DurationSeconds: at.Duration.Seconds(),
+		})
+	}
+	return transitions
+}
+
 func groupToAPI(g *rule.Group) *apiGroup {
 	g = g.DeepCopy()
 	ag := apiGroup{
@@ -356,6 +423,9 @@ func groupToAPI(g *rule.Group) *apiGroup {
 	if g.EvalOffset != nil {
 		ag.EvalOffset = g.EvalOffset.Seconds()
 	}
+	if g.EvalJitter != nil {
+		ag.EvalJitter = g.EvalJitter.Seconds()
+	}
 	if g.EvalDelay != nil {
 		ag.EvalDelay = g.EvalDelay.Seconds()
 	}
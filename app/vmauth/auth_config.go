@@ -30,6 +30,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutil"
 )
 
 var (
@@ -55,6 +56,9 @@ type AuthConfig struct {
 
 	// ms holds all the metrics for the given AuthConfig
 	ms *metrics.Set
+
+	// jwtUsers contains users with jwt section
+	jwtUsers []*UserInfo
 }
 
 // UserInfo is user information read from authConfigPath
@@ -66,27 +70,40 @@ type UserInfo struct {
 	Username    string `yaml:"username,omitempty"`
 	Password    string `yaml:"password,omitempty"`
 
-	URLPrefix              *URLPrefix  `yaml:"url_prefix,omitempty"`
-	DiscoverBackendIPs     *bool       `yaml:"discover_backend_ips,omitempty"`
-	URLMaps                []URLMap    `yaml:"url_map,omitempty"`
-	DumpRequestOnErrors    bool        `yaml:"dump_request_on_errors,omitempty"`
-	HeadersConf            HeadersConf `yaml:",inline"`
-	MaxConcurrentRequests  int         `yaml:"max_concurrent_requests,omitempty"`
-	DefaultURL             *URLPrefix  `yaml:"default_url,omitempty"`
-	RetryStatusCodes       []int       `yaml:"retry_status_codes,omitempty"`
-	LoadBalancingPolicy    string      `yaml:"load_balancing_policy,omitempty"`
-	DropSrcPathPrefixParts *int        `yaml:"drop_src_path_prefix_parts,omitempty"`
-	TLSCAFile              string      `yaml:"tls_ca_file,omitempty"`
-	TLSCertFile            string      `yaml:"tls_cert_file,omitempty"`
-	TLSKeyFile             string      `yaml:"tls_key_file,omitempty"`
-	TLSServerName          string      `yaml:"tls_server_name,omitempty"`
-	TLSInsecureSkipVerify  *bool       `yaml:"tls_insecure_skip_verify,omitempty"`
+	JWT *JWTConfig `yaml:"jwt,omitempty"`
+
+	URLPrefix              *URLPrefix         `yaml:"url_prefix,omitempty"`
+	DiscoverBackendIPs     *bool              `yaml:"discover_backend_ips,omitempty"`
+	URLMaps                []URLMap           `yaml:"url_map,omitempty"`
+	DumpRequestOnErrors    bool               `yaml:"dump_request_on_errors,omitempty"`
+	HeadersConf            HeadersConf        `yaml:",inline"`
+	MaxConcurrentRequests  int                `yaml:"max_concurrent_requests,omitempty"`
+	MaxRequestsPerSecond   int                `yaml:"max_requests_per_second,omitempty"`
+	ResponseCacheTTL       *promutil.Duration `yaml:"response_cache_ttl,omitempty"`
+	DefaultURL             *URLPrefix         `yaml:"default_url,omitempty"`
+	RetryStatusCodes       []int              `yaml:"retry_status_codes,omitempty"`
+	LoadBalancingPolicy    string             `yaml:"load_balancing_policy,omitempty"`
+	DropSrcPathPrefixParts *int               `yaml:"drop_src_path_prefix_parts,omitempty"`
+	MirrorURL              string             `yaml:"mirror_url,omitempty"`
+	MirrorPercent          *float64           `yaml:"mirror_percent,omitempty"`
+	LogsTenant             string             `yaml:"logs_tenant,omitempty"`
+	TLSCAFile              string             `yaml:"tls_ca_file,omitempty"`
+	TLSCertFile            string             `yaml:"tls_cert_file,omitempty"`
+	TLSKeyFile             string             `yaml:"tls_key_file,omitempty"`
+	TLSServerName          string             `yaml:"tls_server_name,omitempty"`
+	TLSInsecureSkipVerify  *bool              `yaml:"tls_insecure_skip_verify,omitempty"`
 
 	MetricLabels map[string]string `yaml:"metric_labels,omitempty"`
 
 	concurrencyLimitCh      chan struct{}
 	concurrencyLimitReached *metrics.Counter
 
+	requestsRateLimiter      *requestsRateLimiter
+	requestsRateLimitReached *metrics.Counter
+
+	responseCache     *responseCache
+	responseCacheHits *metrics.Counter
+
 	rt http.RoundTripper
 
 	requests         *metrics.Counter
@@ -115,6 +132,63 @@ func (ui *UserInfo) endConcurrencyLimit() {
 	<-ui.concurrencyLimitCh
 }
 
+func (ui *UserInfo) checkRequestsRateLimit() error {
+	if ui.requestsRateLimiter.tryRegister() {
+		return nil
+	}
+	ui.requestsRateLimitReached.Inc()
+	return fmt.Errorf("cannot handle more than %d requests per second from user %s", ui.MaxRequestsPerSecond, ui.name())
+}
+
+// requestsRateLimiter limits the number of requests per second.
+type requestsRateLimiter struct {
+	perSecondLimit int
+
+	mu            sync.Mutex
+	currentSecond uint64
+	count         int
+}
+
+func newRequestsRateLimiter(perSecondLimit int) *requestsRateLimiter {
+	if perSecondLimit <= 0 {
+		return nil
+	}
+	return &requestsRateLimiter{
+		perSecondLimit: perSecondLimit,
+	}
+}
+
+// tryRegister returns false if the per-second limit for requests is reached.
+func (rl *requestsRateLimiter) tryRegister() bool {
+	if rl == nil {
+		return true
+	}
+	currentSecond := fasttime.UnixTimestamp()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.currentSecond != currentSecond {
+		rl.currentSecond = currentSecond
+		rl.count = 0
+	}
+	if rl.count >= rl.perSecondLimit {
+		return false
+	}
+	rl.count++
+	return true
+}
+
+func (ui *UserInfo) validateRequestLimits() error {
+	if ui.MaxRequestsPerSecond < 0 {
+		return fmt.Errorf("max_requests_per_second cannot be negative; got %d", ui.MaxRequestsPerSecond)
+	}
+	if ui.ResponseCacheTTL.Duration() < 0 {
+		return fmt.Errorf("response_cache_ttl cannot be negative; got %s", ui.ResponseCacheTTL.Duration())
+	}
+	return nil
+}
+
 func (ui *UserInfo) getMaxConcurrentRequests() int {
 	mcr := ui.MaxConcurrentRequests
 	if mcr <= 0 {
@@ -184,6 +258,15 @@ type URLMap struct {
 
 	// DropSrcPathPrefixParts is the number of `/`-delimited request path prefix parts to drop before proxying the request to backend.
 	DropSrcPathPrefixParts *int `yaml:"drop_src_path_prefix_parts,omitempty"`
+
+	// MirrorURL is the url of the shadow backend, which receives copies of requests routed to UrlPrefix.
+	MirrorURL string `yaml:"mirror_url,omitempty"`
+
+	// MirrorPercent is the percentage of requests to send to MirrorURL.
+	MirrorPercent *float64 `yaml:"mirror_percent,omitempty"`
+
+	// LogsTenant is VictoriaLogs tenant in the form `AccountID:ProjectID`, which is set in request headers.
+	LogsTenant string `yaml:"logs_tenant,omitempty"`
 }
 
 // QueryArg represents HTTP query arg
@@ -239,6 +322,15 @@ type URLPrefix struct {
 	// how many request path prefix parts to drop before routing the request to backendURL
 	dropSrcPathPrefixParts int
 
+	// the url of the shadow backend, which receives copies of the proxied requests
+	mirrorURL *url.URL
+
+	// the percentage of requests to send to mirrorURL
+	mirrorPercent float64
+
+	// VictoriaLogs tenant, which is set in AccountID and ProjectID request headers
+	logsTenant string
+
 	// busOriginal contains the original list of backends specified in yaml config.
 	busOriginal []*url.URL
 
@@ -719,6 +811,10 @@ func reloadAuthConfigData(data []byte) (bool, error) {
 	authConfigData.Store(&data)
 	authUsers.Store(&m)
 
+	for _, ui := range ac.jwtUsers {
+		ui.JWT.keys.prefetch()
+	}
+
 	return true, nil
 }
 
@@ -751,9 +847,15 @@ func parseAuthConfig(data []byte) (*AuthConfig, error) {
 		if ui.Name != "" {
 			return nil, fmt.Errorf("field name can't be specified for unauthorized_user section")
 		}
+		if ui.JWT != nil {
+			return nil, fmt.Errorf("field jwt can't be specified for unauthorized_user section")
+		}
 		if err := ui.initURLs(); err != nil {
 			return nil, err
 		}
+		if err := ui.validateRequestLimits(); err != nil {
+			return nil, fmt.Errorf("invalid unauthorized_user section: %w", err)
+		}
 
 		metricLabels, err := ui.getMetricLabels()
 		if err != nil {
@@ -770,6 +872,16 @@ func parseAuthConfig(data []byte) (*AuthConfig, error) {
 		_ = ac.ms.NewGauge(`vmauth_unauthorized_user_concurrent_requests_current`+metricLabels, func() float64 {
 			return float64(len(ui.concurrencyLimitCh))
 		})
+		ui.requestsRateLimiter = newRequestsRateLimiter(ui.MaxRequestsPerSecond)
+		ui.requestsRateLimitReached = ac.ms.NewCounter(`vmauth_unauthorized_user_requests_rate_limit_reached_total` + metricLabels)
+		if ttl := ui.ResponseCacheTTL.Duration(); ttl > 0 {
+			rc := newResponseCache(ttl, ui)
+			ui.responseCache = rc
+			ui.responseCacheHits = ac.ms.NewCounter(`vmauth_unauthorized_user_response_cache_hits_total` + metricLabels)
+			_ = ac.ms.NewGauge(`vmauth_unauthorized_user_response_cache_size_bytes`+metricLabels, func() float64 {
+				return float64(rc.getSizeBytes())
+			})
+		}
 
 		rt, err := newRoundTripper(ui.TLSCAFile, ui.TLSCertFile, ui.TLSKeyFile, ui.TLSServerName, ui.TLSInsecureSkipVerify)
 		if err != nil {
@@ -787,11 +899,24 @@ func parseAuthConfigUsers(ac *AuthConfig) (map[string]*UserInfo, error) {
 		// fast path for empty configuration
 		return byAuthToken, nil
 	}
+	var jwtUsers []*UserInfo
 	for i := range uis {
 		ui := &uis[i]
-		ats, err := getAuthTokens(ui.AuthToken, ui.BearerToken, ui.Username, ui.Password)
-		if err != nil {
-			return nil, err
+		var ats []string
+		if ui.JWT != nil {
+			if ui.AuthToken != "" || ui.BearerToken != "" || ui.Username != "" || ui.Password != "" {
+				return nil, fmt.Errorf("auth_token, bearer_token, username and password cannot be specified if jwt is set for user %q", ui.name())
+			}
+			if err := ui.JWT.init(); err != nil {
+				return nil, fmt.Errorf("invalid jwt section for user %q: %w", ui.name(), err)
+			}
+			jwtUsers = append(jwtUsers, ui)
+		} else {
+			var err error
+			ats, err = getAuthTokens(ui.AuthToken, ui.BearerToken, ui.Username, ui.Password)
+			if err != nil {
+				return nil, err
+			}
 		}
 		for _, at := range ats {
 			if uiOld := byAuthToken[at]; uiOld != nil {
@@ -802,6 +927,9 @@ func parseAuthConfigUsers(ac *AuthConfig) (map[string]*UserInfo, error) {
 		if err := ui.initURLs(); err != nil {
 			return nil, err
 		}
+		if err := ui.validateRequestLimits(); err != nil {
+			return nil, fmt.Errorf("invalid config for user %q: %w", ui.name(), err)
+		}
 
 		metricLabels, err := ui.getMetricLabels()
 		if err != nil {
@@ -819,6 +947,16 @@ func parseAuthConfigUsers(ac *AuthConfig) (map[string]*UserInfo, error) {
 		_ = ac.ms.GetOrCreateGauge(`vmauth_user_concurrent_requests_current`+metricLabels, func() float64 {
 			return float64(len(ui.concurrencyLimitCh))
 		})
+		ui.requestsRateLimiter = newRequestsRateLimiter(ui.MaxRequestsPerSecond)
+		ui.requestsRateLimitReached = ac.ms.GetOrCreateCounter(`vmauth_user_requests_rate_limit_reached_total` + metricLabels)
+		if ttl := ui.ResponseCacheTTL.Duration(); ttl > 0 {
+			rc := newResponseCache(ttl, ui)
+			ui.responseCache = rc
+			ui.responseCacheHits = ac.ms.GetOrCreateCounter(`vmauth_user_response_cache_hits_total` + metricLabels)
+			_ = ac.ms.GetOrCreateGauge(`vmauth_user_response_cache_size_bytes`+metricLabels, func() float64 {
+				return float64(rc.getSizeBytes())
+			})
+		}
 
 		rt, err := newRoundTripper(ui.TLSCAFile, ui.TLSCertFile, ui.TLSKeyFile, ui.TLSServerName, ui.TLSInsecureSkipVerify)
 		if err != nil {
@@ -830,6 +968,7 @@ func parseAuthConfigUsers(ac *AuthConfig) (map[string]*UserInfo, error) {
 			byAuthToken[at] = ui
 		}
 	}
+	ac.jwtUsers = jwtUsers
 	return byAuthToken, nil
 }
 
@@ -873,6 +1012,14 @@ func (ui *UserInfo) initURLs() error {
 	if ui.DiscoverBackendIPs != nil {
 		discoverBackendIPs = *ui.DiscoverBackendIPs
 	}
+	mirrorURL, mirrorPercent, err := parseMirrorConfig(ui.MirrorURL, ui.MirrorPercent, nil, 100)
+	if err != nil {
+		return err
+	}
+	logsTenant := ui.LogsTenant
+	if err := validateLogsTenant(logsTenant); err != nil {
+		return err
+	}
 
 	if ui.URLPrefix != nil {
 		if err := ui.URLPrefix.sanitizeAndInitialize(); err != nil {
@@ -881,6 +1028,9 @@ func (ui *UserInfo) initURLs() error {
 		ui.URLPrefix.retryStatusCodes = retryStatusCodes
 		ui.URLPrefix.dropSrcPathPrefixParts = dropSrcPathPrefixParts
 		ui.URLPrefix.discoverBackendIPs = discoverBackendIPs
+		ui.URLPrefix.mirrorURL = mirrorURL
+		ui.URLPrefix.mirrorPercent = mirrorPercent
+		ui.URLPrefix.logsTenant = logsTenant
 		if err := ui.URLPrefix.setLoadBalancingPolicy(loadBalancingPolicy); err != nil {
 			return err
 		}
@@ -889,6 +1039,7 @@ func (ui *UserInfo) initURLs() error {
 		if err := ui.DefaultURL.sanitizeAndInitialize(); err != nil {
 			return err
 		}
+		ui.DefaultURL.logsTenant = logsTenant
 	}
 	for _, e := range ui.URLMaps {
 		if len(e.SrcPaths) == 0 && len(e.SrcHosts) == 0 && len(e.SrcQueryArgs) == 0 && len(e.SrcHeaders) == 0 {
@@ -922,11 +1073,53 @@ func (ui *UserInfo) initURLs() error {
 		}
 		e.URLPrefix.dropSrcPathPrefixParts = dsp
 		e.URLPrefix.discoverBackendIPs = dbd
+		mu, mp, err := parseMirrorConfig(e.MirrorURL, e.MirrorPercent, mirrorURL, mirrorPercent)
+		if err != nil {
+			return err
+		}
+		e.URLPrefix.mirrorURL = mu
+		e.URLPrefix.mirrorPercent = mp
+		lt := logsTenant
+		if e.LogsTenant != "" {
+			if err := validateLogsTenant(e.LogsTenant); err != nil {
+				return err
+			}
+			lt = e.LogsTenant
+		}
+		e.URLPrefix.logsTenant = lt
 	}
 	if len(ui.URLMaps) == 0 && ui.URLPrefix == nil {
 		return fmt.Errorf("missing `url_prefix` or `url_map`")
 	}
-	return nil
+	return ui.checkTenantPlaceholder()
+}
+
+// parseMirrorConfig parses `mirror_url` and `mirror_percent` options.
+//
+// defaultURL and defaultPercent are returned if the corresponding options are missing.
+func parseMirrorConfig(mirrorURL string, mirrorPercent *float64, defaultURL *url.URL, defaultPercent float64) (*url.URL, float64, error) {
+	u := defaultURL
+	if mirrorURL != "" {
+		pu, err := url.Parse(mirrorURL)
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot parse `mirror_url: %q`: %w", mirrorURL, err)
+		}
+		if pu.Scheme != "http" && pu.Scheme != "https" {
+			return nil, 0, fmt.Errorf("unsupported scheme for `mirror_url: %q`: %q; must be `http` or `https`", mirrorURL, pu.Scheme)
+		}
+		if pu.Host == "" {
+			return nil, 0, fmt.Errorf("missing hostname in `mirror_url: %q`", mirrorURL)
+		}
+		u = pu
+	}
+	percent := defaultPercent
+	if mirrorPercent != nil {
+		percent = *mirrorPercent
+		if percent < 0 || percent > 100 {
+			return nil, 0, fmt.Errorf("`mirror_percent: %v` must be in the range [0..100]", percent)
+		}
+	}
+	return u, percent, nil
 }
 
 func (ui *UserInfo) name() string {
@@ -6,11 +6,13 @@ import (
 	"net"
 	"net/url"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutil"
 )
 
 func TestParseAuthConfigFailure(t *testing.T) {
@@ -36,6 +38,56 @@ users:
 - username: foo
 `)
 
+	// Invalid logs_tenant
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo
+  logs_tenant: bar
+`)
+	f(`
+users:
+- username: foo
+  url_map:
+  - src_paths: ["/foo"]
+    url_prefix: http://foo
+    logs_tenant: "1:2:3"
+`)
+
+	// Invalid mirror_url
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo
+  mirror_url: ftp://bar
+`)
+	f(`
+users:
+- username: foo
+  url_map:
+  - src_paths: ["/foo"]
+    url_prefix: http://foo
+    mirror_url: http:///bar
+`)
+
+	// Invalid mirror_percent
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo
+  mirror_url: http://bar
+  mirror_percent: 101
+`)
+	f(`
+users:
+- username: foo
+  url_map:
+  - src_paths: ["/foo"]
+    url_prefix: http://foo
+    mirror_url: http://bar
+    mirror_percent: -1
+`)
+
 	// Invalid url_prefix
 	f(`
 users:
@@ -269,6 +321,77 @@ users:
     headers:
       aaa: bbb
 `)
+	// jwt with username
+	f(`
+users:
+- username: foo
+  jwt:
+    jwks_url: http://idp/jwks
+  url_prefix: http://foo.bar
+`)
+
+	// jwt without jwks_url
+	f(`
+users:
+- name: foo
+  jwt:
+    issuer: http://idp
+  url_prefix: http://foo.bar
+`)
+
+	// {{tenant}} placeholder without tenant_claim
+	f(`
+users:
+- name: foo
+  jwt:
+    jwks_url: http://idp/jwks
+  url_prefix: http://vmselect:8481/select/{{tenant}}/prometheus
+`)
+	f(`
+users:
+- name: foo
+  jwt:
+    jwks_url: http://idp/jwks
+  url_map:
+  - src_paths: ["/insert/.*"]
+    url_prefix: http://victorialogs:9428
+    logs_tenant: "{{tenant}}"
+`)
+	f(`
+users:
+- username: foo
+  url_prefix: http://vmselect:8481/select/{{tenant}}/prometheus
+`)
+	f(`
+unauthorized_user:
+  url_map:
+  - src_paths: ["/api/v1/write"]
+    url_prefix: http://vminsert:8480/insert/{{tenant}}/prometheus
+`)
+
+	// jwt in unauthorized_user
+	f(`
+unauthorized_user:
+  jwt:
+    jwks_url: http://idp/jwks
+  url_prefix: http://foo.bar
+`)
+
+	// Negative max_requests_per_second
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo.bar
+  max_requests_per_second: -1
+`)
+
+	// Negative response_cache_ttl
+	f(`
+unauthorized_user:
+  url_prefix: http://foo.bar
+  response_cache_ttl: -1s
+`)
+
 	// Invalid metric label name
 	f(`
 users:
@@ -322,6 +445,32 @@ users:
 		},
 	})
 
+	// Single user with requests limits
+	f(`
+users:
+- username: foo
+  url_prefix: http://aaa:343/bbb
+  max_requests_per_second: 10
+  response_cache_ttl: 5s
+`, map[string]*UserInfo{
+		getHTTPAuthBasicToken("foo", ""): {
+			Username:             "foo",
+			URLPrefix:            mustParseURL("http://aaa:343/bbb"),
+			MaxRequestsPerSecond: 10,
+			ResponseCacheTTL:     promutil.NewDuration(5 * time.Second),
+		},
+	})
+
+	// User with jwt isn't accessible by auth tokens
+	f(`
+users:
+- name: foo
+  jwt:
+    jwks_url: http://idp/jwks
+    tenant_claim: org_id
+  url_prefix: http://vmselect:8481/select/{{tenant}}/prometheus
+`, map[string]*UserInfo{})
+
 	// Single user with auth_token
 	f(`
 users:
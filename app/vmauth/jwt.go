@@ -0,0 +1,441 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var (
+	jwksRefreshInterval = flag.Duration("jwt.jwksRefreshInterval", 5*time.Minute, "How often to refresh JSON Web Key Sets from jwks_url options for users with jwt section. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmauth/#jwt-authorization")
+	jwksRequestTimeout = flag.Duration("jwt.jwksRequestTimeout", 10*time.Second, "Timeout for requests to jwks_url options for users with jwt section. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmauth/#jwt-authorization")
+)
+
+// tenantPlaceholder is replaced with the value of tenant_claim from JWT in url_prefix paths.
+const tenantPlaceholder = "{{tenant}}"
+
+// jwksMinRefreshInterval is the minimum interval between JWKS refreshes on unknown key ids.
+//
+// It protects jwks_url from being flooded by requests with tokens signed by unknown keys.
+const jwksMinRefreshInterval = 10 * time.Second
+
+// JWTConfig represents `jwt` section for users authorized via JSON Web Tokens.
+type JWTConfig struct {
+	// JWKSURL is the url for obtaining JSON Web Key Set for verifying token signatures.
+	JWKSURL string `yaml:"jwks_url"`
+	// Issuer is an optional expected value of `iss` claim.
+	Issuer string `yaml:"issuer,omitempty"`
+	// Audience is an optional expected value of `aud` claim.
+	Audience string `yaml:"audience,omitempty"`
+	// TenantClaim is an optional name of the claim, which value is substituted
+	// instead of {{tenant}} placeholder in url_prefix.
+	TenantClaim string `yaml:"tenant_claim,omitempty"`
+
+	parser *jwt.Parser
+	keys   *jwksCache
+}
+
+var validJWTAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+func (jc *JWTConfig) init() error {
+	if jc.JWKSURL == "" {
+		return fmt.Errorf("missing `jwks_url` in `jwt` section")
+	}
+	if _, err := url.Parse(jc.JWKSURL); err != nil {
+		return fmt.Errorf("cannot parse `jwks_url` %q: %w", jc.JWKSURL, err)
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validJWTAlgs),
+		jwt.WithExpirationRequired(),
+	}
+	if jc.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(jc.Issuer))
+	}
+	if jc.Audience != "" {
+		opts = append(opts, jwt.WithAudience(jc.Audience))
+	}
+	jc.parser = jwt.NewParser(opts...)
+	jc.keys = &jwksCache{
+		url: jc.JWKSURL,
+	}
+	return nil
+}
+
+var tenantRegexp = regexp.MustCompile(`^[a-zA-Z0-9_:-]+$`)
+
+// verify verifies the given token and returns the tenant from TenantClaim.
+func (jc *JWTConfig) verify(token string) (string, error) {
+	var claims jwt.MapClaims
+	_, err := jc.parser.ParseWithClaims(token, &claims, jc.getKey)
+	if err != nil {
+		return "", err
+	}
+	if jc.TenantClaim == "" {
+		return "", nil
+	}
+	v, ok := claims[jc.TenantClaim]
+	if !ok {
+		return "", fmt.Errorf("missing %q claim", jc.TenantClaim)
+	}
+	var tenant string
+	switch t := v.(type) {
+	case string:
+		tenant = t
+	case float64:
+		tenant = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("unsupported type %T for %q claim; want string or number", v, jc.TenantClaim)
+	}
+	if !tenantRegexp.MatchString(tenant) {
+		return "", fmt.Errorf("invalid value %q for %q claim; it must match %s", tenant, jc.TenantClaim, tenantRegexp)
+	}
+	return tenant, nil
+}
+
+func (jc *JWTConfig) getKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	return jc.keys.getKey(kid)
+}
+
+// jwksCache holds public keys obtained from url.
+//
+// Requests with known key ids never wait for fetching keys from url,
+// since the keys are refreshed in background and are swapped atomically.
+type jwksCache struct {
+	url string
+
+	// keys contains the last successfully fetched keys.
+	keys atomic.Pointer[map[string]any]
+
+	// lastFetch is the time in unix nanoseconds of the last attempt to fetch keys from url.
+	lastFetch atomic.Int64
+
+	// fetchMu serializes fetching keys from url.
+	fetchMu sync.Mutex
+
+	// isRefreshing is set to true while keys are refreshed in background.
+	isRefreshing atomic.Bool
+}
+
+func (jc *jwksCache) getKey(kid string) (any, error) {
+	if key, ok := jc.lookupKey(kid); ok {
+		if jc.sinceLastFetch() > *jwksRefreshInterval && jc.isRefreshing.CompareAndSwap(false, true) {
+			go func() {
+				jc.fetchMu.Lock()
+				jc.fetchKeysLocked()
+				jc.fetchMu.Unlock()
+				jc.isRefreshing.Store(false)
+			}()
+		}
+		return key, nil
+	}
+
+	// The key could be rotated at the identity provider, so fetch fresh keys.
+	// Fetches for unknown key ids are rate-limited in order to protect url from floods of tokens signed by unknown keys.
+	jc.fetchMu.Lock()
+	if _, ok := jc.lookupKey(kid); !ok && jc.sinceLastFetch() > jwksMinRefreshInterval {
+		jc.fetchKeysLocked()
+	}
+	jc.fetchMu.Unlock()
+
+	key, ok := jc.lookupKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("cannot find key with kid=%q at %q", kid, jc.url)
+	}
+	return key, nil
+}
+
+// prefetch fetches keys from url in background if they weren't fetched yet.
+//
+// This prevents the first requests after config reload from waiting for keys fetch.
+func (jc *jwksCache) prefetch() {
+	go func() {
+		jc.fetchMu.Lock()
+		if jc.keys.Load() == nil {
+			jc.fetchKeysLocked()
+		}
+		jc.fetchMu.Unlock()
+	}()
+}
+
+func (jc *jwksCache) lookupKey(kid string) (any, bool) {
+	keysP := jc.keys.Load()
+	if keysP == nil {
+		return nil, false
+	}
+	keys := *keysP
+	if kid == "" && len(keys) == 1 {
+		// The token has no kid header, while JWKS contains a single key. Use it.
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (jc *jwksCache) sinceLastFetch() time.Duration {
+	return time.Since(time.Unix(0, jc.lastFetch.Load()))
+}
+
+// fetchKeysLocked fetches keys from url.
+//
+// It must be called under fetchMu lock.
+func (jc *jwksCache) fetchKeysLocked() {
+	jc.lastFetch.Store(time.Now().UnixNano())
+	keys, err := fetchJWKS(jc.url)
+	if err != nil {
+		logger.Errorf("cannot refresh JSON Web Key Set from %q: %s", jc.url, err)
+		return
+	}
+	jc.keys.Store(&keys)
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func fetchJWKS(jwksURL string) (map[string]any, error) {
+	client := &http.Client{
+		Timeout: *jwksRequestTimeout,
+	}
+	resp, err := client.Get(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d; response body: %q", resp.StatusCode, data)
+	}
+	return parseJWKS(data)
+}
+
+func parseJWKS(data []byte) (map[string]any, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("cannot parse JSON Web Key Set: %w", err)
+	}
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse key with kid=%q: %w", k.Kid, err)
+		}
+		if key == nil {
+			// Unsupported key type.
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode `n`: %w", err)
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode `e`: %w", err)
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("too big `e`")
+		}
+		return &rsa.PublicKey{
+			N: n,
+			E: int(e.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode `x`: %w", err)
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode `y`: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// unverifiedJWTParser is used for reading token fields before signature verification.
+var unverifiedJWTParser = jwt.NewParser()
+
+// getUserInfoByJWT returns the user with jwt section, which successfully verifies bearer token from ats.
+//
+// It also returns the tenant obtained from the token.
+func getUserInfoByJWT(ats []string) (*UserInfo, string) {
+	uis := authConfig.Load().jwtUsers
+	if len(uis) == 0 {
+		return nil, ""
+	}
+	for _, at := range ats {
+		token, ok := strings.CutPrefix(at, "http_auth:Bearer ")
+		if !ok || strings.Count(token, ".") != 2 {
+			continue
+		}
+		var claims jwt.MapClaims
+		t, _, err := unverifiedJWTParser.ParseUnverified(token, &claims)
+		if err != nil {
+			if *logInvalidAuthTokens {
+				logger.Infof("cannot parse JWT: %s", err)
+			}
+			continue
+		}
+		kid, _ := t.Header["kid"].(string)
+
+		// Signature verification is expensive, so verify the token only for users, which may accept it.
+		// Do not verify the token again with keys from jwks_url, which already failed the signature verification.
+		var badSignatureURLs []string
+		for _, ui := range getJWTCandidates(uis, claims, kid) {
+			if slices.Contains(badSignatureURLs, ui.JWT.JWKSURL) {
+				continue
+			}
+			tenant, err := ui.JWT.verify(token)
+			if err != nil {
+				if *logInvalidAuthTokens {
+					logger.Infof("cannot verify JWT for user %q: %s", ui.name(), err)
+				}
+				if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+					badSignatureURLs = append(badSignatureURLs, ui.JWT.JWKSURL)
+				}
+				continue
+			}
+			return ui, tenant
+		}
+	}
+	return nil, ""
+}
+
+// getJWTCandidates returns users from uis, which may accept the token with the given unverified claims and kid.
+//
+// Users with the known kid are returned first. Users with unknown kid are returned last,
+// since the token may be signed by the rotated key, which isn't fetched from jwks_url yet.
+func getJWTCandidates(uis []*UserInfo, claims jwt.MapClaims, kid string) []*UserInfo {
+	iss, _ := claims.GetIssuer()
+	aud, _ := claims.GetAudience()
+
+	var candidates, unknownKid []*UserInfo
+	for _, ui := range uis {
+		jc := ui.JWT
+		if jc.Issuer != "" && jc.Issuer != iss {
+			continue
+		}
+		if jc.Audience != "" && !slices.Contains(aud, jc.Audience) {
+			continue
+		}
+		if _, ok := jc.keys.lookupKey(kid); ok {
+			candidates = append(candidates, ui)
+		} else {
+			unknownKid = append(unknownKid, ui)
+		}
+	}
+	return append(candidates, unknownKid...)
+}
+
+// checkTenantPlaceholder returns an error if ui uses {{tenant}} placeholder, which cannot be substituted,
+// since ui has no `jwt` section with `tenant_claim`.
+func (ui *UserInfo) checkTenantPlaceholder() error {
+	if ui.JWT != nil && ui.JWT.TenantClaim != "" {
+		return nil
+	}
+	ups := []*URLPrefix{ui.URLPrefix, ui.DefaultURL}
+	for _, e := range ui.URLMaps {
+		ups = append(ups, e.URLPrefix)
+	}
+	for _, up := range ups {
+		if up != nil && up.hasTenantPlaceholder() {
+			return fmt.Errorf("%s placeholder at `url_prefix: %v` requires `tenant_claim` in `jwt` section", tenantPlaceholder, up.vOriginal)
+		}
+	}
+	return nil
+}
+
+func (up *URLPrefix) hasTenantPlaceholder() bool {
+	for _, bu := range up.busOriginal {
+		if strings.Contains(bu.Path, tenantPlaceholder) {
+			return true
+		}
+	}
+	if up.mirrorURL != nil && strings.Contains(up.mirrorURL.Path, tenantPlaceholder) {
+		return true
+	}
+	return up.logsTenant == tenantPlaceholder
+}
+
+// setTenant returns u with {{tenant}} placeholder replaced by tenant in path.
+func setTenant(u *url.URL, tenant string) *url.URL {
+	if !strings.Contains(u.Path, tenantPlaceholder) {
+		return u
+	}
+	uCopy := *u
+	uCopy.Path = strings.ReplaceAll(u.Path, tenantPlaceholder, tenant)
+	uCopy.RawPath = ""
+	return &uCopy
+}
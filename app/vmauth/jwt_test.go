@@ -0,0 +1,332 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTConfigVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	jwks := fmt.Sprintf(`{"keys":[{"kid":"k1","kty":"RSA","use":"sig","alg":"RS256","n":%q,"e":%q}]}`, n, e)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, jwks)
+	}))
+	defer ts.Close()
+
+	jc := &JWTConfig{
+		JWKSURL:     ts.URL,
+		Issuer:      "https://idp",
+		Audience:    "vm",
+		TenantClaim: "org_id",
+	}
+	if err := jc.init(); err != nil {
+		t.Fatalf("cannot init jwt config: %s", err)
+	}
+
+	signToken := func(key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+		t.Helper()
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("cannot sign token: %s", err)
+		}
+		return s
+	}
+
+	fSuccess := func(token, tenantExpected string) {
+		t.Helper()
+
+		tenant, err := jc.verify(token)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if tenant != tenantExpected {
+			t.Fatalf("unexpected tenant; got %q; want %q", tenant, tenantExpected)
+		}
+	}
+	fFailure := func(token string) {
+		t.Helper()
+
+		if _, err := jc.verify(token); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	// string tenant
+	fSuccess(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"exp":    exp,
+		"org_id": "42:1",
+	}), "42:1")
+
+	// numeric tenant
+	fSuccess(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    []string{"grafana", "vm"},
+		"exp":    exp,
+		"org_id": 42,
+	}), "42")
+
+	// invalid issuer
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://other-idp",
+		"aud":    "vm",
+		"exp":    exp,
+		"org_id": "42",
+	}))
+
+	// invalid audience
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "grafana",
+		"exp":    exp,
+		"org_id": "42",
+	}))
+
+	// expired token
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"exp":    time.Now().Add(-time.Hour).Unix(),
+		"org_id": "42",
+	}))
+
+	// missing exp
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"org_id": "42",
+	}))
+
+	// missing tenant claim
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss": "https://idp",
+		"aud": "vm",
+		"exp": exp,
+	}))
+
+	// invalid tenant claim
+	fFailure(signToken(key, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"exp":    exp,
+		"org_id": "../../foo",
+	}))
+
+	// token signed by unknown key
+	fFailure(signToken(otherKey, "k1", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"exp":    exp,
+		"org_id": "42",
+	}))
+
+	// token with unknown kid
+	fFailure(signToken(key, "k2", jwt.MapClaims{
+		"iss":    "https://idp",
+		"aud":    "vm",
+		"exp":    exp,
+		"org_id": "42",
+	}))
+
+	// malformed token
+	fFailure("foo.bar.baz")
+}
+
+func TestJWKSCacheGetKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	jwks := fmt.Sprintf(`{"keys":[{"kid":"k1","kty":"RSA","use":"sig","alg":"RS256","n":%q,"e":%q}]}`, n, e)
+
+	var fetches atomic.Int32
+	unblockCh := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) > 1 {
+			// block refreshes until the end of the test
+			<-unblockCh
+		}
+		fmt.Fprint(w, jwks)
+	}))
+	defer ts.Close()
+	defer close(unblockCh)
+
+	jc := &jwksCache{
+		url: ts.URL,
+	}
+
+	// the first request fetches keys
+	if _, err := jc.getKey("k1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("unexpected number of fetches; got %d; want 1", n)
+	}
+
+	// unknown kid doesn't lead to fetches more frequently than jwksMinRefreshInterval
+	for i := 0; i < 10; i++ {
+		if _, err := jc.getKey("k2"); err == nil {
+			t.Fatalf("expecting non-nil error for unknown kid")
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("unexpected number of fetches; got %d; want 1", n)
+	}
+
+	// requests with known kid do not wait for background refresh of stale keys
+	jc.lastFetch.Store(time.Now().Add(-2 * *jwksRefreshInterval).UnixNano())
+	deadline := time.Now().Add(time.Second)
+	for i := 0; i < 10; i++ {
+		if _, err := jc.getKey("k1"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if time.Now().After(deadline) {
+		t.Fatalf("requests with known kid must not wait for keys refresh")
+	}
+	for fetches.Load() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout when waiting for background refresh")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !jc.isRefreshing.Load() {
+		t.Fatalf("expecting in-progress background refresh")
+	}
+}
+
+func TestJWKSCachePrefetch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	jwks := fmt.Sprintf(`{"keys":[{"kid":"k1","kty":"RSA","use":"sig","alg":"RS256","n":%q,"e":%q}]}`, n, e)
+
+	var fetches atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		fmt.Fprint(w, jwks)
+	}))
+	defer ts.Close()
+
+	jc := &jwksCache{
+		url: ts.URL,
+	}
+	jc.prefetch()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := jc.lookupKey("k1"); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout when waiting for keys prefetch")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// requests after the prefetch use already fetched keys
+	if _, err := jc.getKey("k1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("unexpected number of fetches; got %d; want 1", n)
+	}
+}
+
+func TestGetJWTCandidates(t *testing.T) {
+	newUser := func(name, issuer, audience string, kids ...string) *UserInfo {
+		keys := make(map[string]any)
+		for _, kid := range kids {
+			keys[kid] = struct{}{}
+		}
+		jc := &JWTConfig{
+			JWKSURL:  "http://idp/" + name,
+			Issuer:   issuer,
+			Audience: audience,
+			keys:     &jwksCache{},
+		}
+		jc.keys.keys.Store(&keys)
+		return &UserInfo{
+			Name: name,
+			JWT:  jc,
+		}
+	}
+	uis := []*UserInfo{
+		newUser("unknown-kid", "https://idp1", "", "k9"),
+		newUser("idp1", "https://idp1", "", "k1"),
+		newUser("idp2", "https://idp2", "", "k1"),
+		newUser("idp1-grafana", "https://idp1", "grafana", "k1"),
+		newUser("any-issuer", "", "", "k2"),
+	}
+
+	f := func(claims jwt.MapClaims, kid string, namesExpected []string) {
+		t.Helper()
+
+		var names []string
+		for _, ui := range getJWTCandidates(uis, claims, kid) {
+			names = append(names, ui.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(namesExpected) {
+			t.Fatalf("unexpected candidates; got %q; want %q", names, namesExpected)
+		}
+	}
+
+	// users with the known kid go first
+	f(jwt.MapClaims{"iss": "https://idp1"}, "k1", []string{"idp1", "unknown-kid", "any-issuer"})
+
+	// users with the matching audience
+	f(jwt.MapClaims{"iss": "https://idp1", "aud": []string{"vm", "grafana"}}, "k1", []string{"idp1", "idp1-grafana", "unknown-kid", "any-issuer"})
+
+	// users with other issuers are skipped
+	f(jwt.MapClaims{"iss": "https://idp2"}, "k1", []string{"idp2", "any-issuer"})
+	f(jwt.MapClaims{"iss": "https://idp3"}, "k2", []string{"any-issuer"})
+}
+
+func TestSetTenant(t *testing.T) {
+	f := func(s, tenant, resultExpected string) {
+		t.Helper()
+
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %s", s, err)
+		}
+		result := setTenant(u, tenant).String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result; got %q; want %q", result, resultExpected)
+		}
+	}
+
+	f("http://vmselect:8481/select/{{tenant}}/prometheus/api/v1/query?query=up", "42:1", "http://vmselect:8481/select/42:1/prometheus/api/v1/query?query=up")
+	f("http://vmselect:8481/select/0/prometheus/api/v1/query", "42", "http://vmselect:8481/select/0/prometheus/api/v1/query")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
+)
+
+// validateLogsTenant validates `logs_tenant` option value.
+//
+// The value must be either in the form `AccountID:ProjectID` or `AccountID`, or be equal to {{tenant}} placeholder.
+func validateLogsTenant(s string) error {
+	if s == "" || s == tenantPlaceholder {
+		return nil
+	}
+	if _, err := auth.NewToken(s); err != nil {
+		return fmt.Errorf("cannot parse `logs_tenant: %q`: %w", s, err)
+	}
+	return nil
+}
+
+// getLogsTenant returns VictoriaLogs tenant for the request routed to up.
+//
+// tenant is substituted instead of {{tenant}} placeholder in `logs_tenant`.
+// nil is returned if `logs_tenant` isn't set for up.
+func (up *URLPrefix) getLogsTenant(tenant string) (*auth.Token, error) {
+	s := up.logsTenant
+	if s == "" {
+		return nil, nil
+	}
+	if s == tenantPlaceholder {
+		if tenant == "" {
+			return nil, fmt.Errorf("missing tenant for {{tenant}} placeholder in `logs_tenant`")
+		}
+		s = tenant
+	}
+	t, err := auth.NewToken(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse VictoriaLogs tenant %q: %w", s, err)
+	}
+	return t, nil
+}
+
+// setLogsTenantHeaders sets VictoriaLogs tenant headers at h to t.
+//
+// The headers passed by client are overridden, so clients cannot access other tenants.
+func setLogsTenantHeaders(h http.Header, t *auth.Token) {
+	h.Set("AccountID", strconv.FormatUint(uint64(t.AccountID), 10))
+	h.Set("ProjectID", strconv.FormatUint(uint64(t.ProjectID), 10))
+}
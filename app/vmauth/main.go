@@ -157,7 +157,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		// Process requests for unauthorized users
 		ui := authConfig.Load().UnauthorizedUser
 		if ui != nil {
-			processUserRequest(w, r, ui)
+			processUserRequest(w, r, ui, "")
 			return true
 		}
 
@@ -167,9 +167,14 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 
 	ui := getUserInfoByAuthTokens(ats)
 	if ui == nil {
+		if jwtUI, tenant := getUserInfoByJWT(ats); jwtUI != nil {
+			processUserRequest(w, r, jwtUI, tenant)
+			return true
+		}
+
 		uu := authConfig.Load().UnauthorizedUser
 		if uu != nil {
-			processUserRequest(w, r, uu)
+			processUserRequest(w, r, uu, "")
 			return true
 		}
 
@@ -187,7 +192,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	}
 
-	processUserRequest(w, r, ui)
+	processUserRequest(w, r, ui, "")
 	return true
 }
 
@@ -202,12 +207,19 @@ func getUserInfoByAuthTokens(ats []string) *UserInfo {
 	return nil
 }
 
-func processUserRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
+// processUserRequest proxies r to the backend configured for ui.
+//
+// tenant is substituted instead of {{tenant}} placeholder in the backend url path if it isn't empty.
+func processUserRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo, tenant string) {
 	startTime := time.Now()
 	defer ui.requestsDuration.UpdateDuration(startTime)
 
 	ui.requests.Inc()
 
+	if err := ui.checkRequestsRateLimit(); err != nil {
+		handleConcurrencyLimitError(w, r, err)
+		return
+	}
 	// Limit the concurrency of requests to backends
 	concurrencyLimitOnce.Do(concurrencyLimitInit)
 	select {
@@ -223,12 +235,12 @@ func processUserRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 		handleConcurrencyLimitError(w, r, err)
 		return
 	}
-	processRequest(w, r, ui)
+	processRequest(w, r, ui, tenant)
 	ui.endConcurrencyLimit()
 	<-concurrencyLimitCh
 }
 
-func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
+func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo, tenant string) {
 	u := normalizeURL(r.URL)
 	up, hc := ui.getURLPrefixAndHeaders(u, r.Host, r.Header)
 	isDefault := false
@@ -237,7 +249,7 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 			// Authorization should be requested for http requests without credentials
 			// to a route that is not in the configuration for unauthorized user.
 			// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/5236
-			if ui.BearerToken == "" && ui.Username == "" && len(*authUsers.Load()) > 0 {
+			if ui.BearerToken == "" && ui.Username == "" && ui.JWT == nil && len(*authUsers.Load()) > 0 {
 				handleMissingAuthorizationError(w)
 				return
 			}
@@ -253,6 +265,39 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 		isDefault = true
 	}
 
+	// The cache key must be obtained before updating request headers below.
+	var cacheKey string
+	if ui.responseCache != nil && isCacheableRequest(r) {
+		cacheKey = ui.responseCache.getKey(r, ui.getRouteIndex(up), tenant)
+		if ui.responseCache.tryServe(w, cacheKey) {
+			ui.responseCacheHits.Inc()
+			return
+		}
+	}
+
+	lt, ltErr := up.getLogsTenant(tenant)
+	if ltErr != nil {
+		err := &httpserver.ErrorWithStatusCode{
+			Err:        fmt.Errorf("cannot determine VictoriaLogs tenant for the user %q: %w", ui.name(), ltErr),
+			StatusCode: http.StatusBadRequest,
+		}
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	if lt != nil {
+		setLogsTenantHeaders(r.Header, lt)
+	}
+
+	if !isDefault && up.shouldMirror() {
+		mb := newMirrorBody(r.Body, mirrorMaxRequestBodySize.IntN())
+		r.Body = mb
+		mirrorURL := mergeURLs(up.mirrorURL, u, up.dropSrcPathPrefixParts)
+		if tenant != "" {
+			mirrorURL = setTenant(mirrorURL, tenant)
+		}
+		defer mirrorRequest(r, mb, mirrorURL, hc, ui)
+	}
+
 	rtb := newReadTrackingBody(r.Body, maxRequestBodySizeToRetry.IntN())
 	r.Body = rtb
 
@@ -271,10 +316,13 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 		} else { // Update path for regular routes.
 			targetURL = mergeURLs(targetURL, u, up.dropSrcPathPrefixParts)
 		}
+		if tenant != "" {
+			targetURL = setTenant(targetURL, tenant)
+		}
 
 		wasLocalRetry := false
 	again:
-		ok, needLocalRetry := tryProcessingRequest(w, r, targetURL, hc, up.retryStatusCodes, ui)
+		ok, needLocalRetry := tryProcessingRequest(w, r, targetURL, hc, up.retryStatusCodes, ui, cacheKey)
 		if needLocalRetry && !wasLocalRetry {
 			wasLocalRetry = true
 			goto again
@@ -294,7 +342,7 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 	ui.backendErrors.Inc()
 }
 
-func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url.URL, hc HeadersConf, retryStatusCodes []int, ui *UserInfo) (bool, bool) {
+func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url.URL, hc HeadersConf, retryStatusCodes []int, ui *UserInfo, cacheKey string) (bool, bool) {
 	req := sanitizeRequestHeaders(r)
 
 	req.URL = targetURL
@@ -372,9 +420,19 @@ func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url
 	updateHeadersByConfig(w.Header(), hc.ResponseHeaders)
 	w.WriteHeader(res.StatusCode)
 
+	var dst io.Writer = w
+	var cw *cachingWriter
+	if cacheKey != "" && isCacheableResponse(res) {
+		cw = &cachingWriter{
+			w:       w,
+			maxSize: responseCacheMaxEntrySize.IntN(),
+		}
+		dst = cw
+	}
+
 	copyBuf := copyBufPool.Get()
 	copyBuf.B = bytesutil.ResizeNoCopyNoOverallocate(copyBuf.B, 16*1024)
-	_, err = io.CopyBuffer(w, res.Body, copyBuf.B)
+	_, err = io.CopyBuffer(dst, res.Body, copyBuf.B)
 	copyBufPool.Put(copyBuf)
 	_ = res.Body.Close()
 	if err != nil && !netutil.IsTrivialNetworkError(err) {
@@ -383,6 +441,9 @@ func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url
 		logger.Warnf("remoteAddr: %s; requestURI: %s; error when proxying response body from %s: %s", remoteAddr, requestURI, targetURL, err)
 		return true, false
 	}
+	if err == nil && cw != nil && !cw.overflow {
+		ui.responseCache.put(cacheKey, res.StatusCode, w.Header().Clone(), cw.buf)
+	}
 	return true, false
 }
 
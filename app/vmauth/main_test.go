@@ -10,7 +10,9 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
 )
 
@@ -90,6 +92,34 @@ User-Agent: vmauth
 X-Forwarded-For: 12.34.56.78, 42.2.3.84`
 	f(cfgStr, requestURL, backendHandler, responseExpected)
 
+	// logs_tenant at user level and at url_map level
+	cfgStr = `
+unauthorized_user:
+  logs_tenant: "12:34"
+  url_map:
+  - src_paths: ["/select/.*"]
+    url_prefix: "{BACKEND}/foo"
+  - src_paths: ["/insert/.*"]
+    url_prefix: "{BACKEND}/bar"
+    logs_tenant: "56"`
+	requestURL = "http://some-host.com/select/logsql/query"
+	backendHandler = func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "requested_url=http://%s%s\nAccountID=%s\nProjectID=%s", r.Host, r.URL, r.Header.Get("AccountID"), r.Header.Get("ProjectID"))
+	}
+	responseExpected = `
+statusCode=200
+requested_url={BACKEND}/foo/select/logsql/query
+AccountID=12
+ProjectID=34`
+	f(cfgStr, requestURL, backendHandler, responseExpected)
+	requestURL = "http://some-host.com/insert/jsonline"
+	responseExpected = `
+statusCode=200
+requested_url={BACKEND}/bar/insert/jsonline
+AccountID=56
+ProjectID=0`
+	f(cfgStr, requestURL, backendHandler, responseExpected)
+
 	// routing of all failed to authorize requests to unauthorized_user (issue #7543)
 	cfgStr = `
 unauthorized_user:
@@ -504,6 +534,297 @@ requested_url={BACKEND}/path2/foo/?de=fg`
 	}
 }
 
+func TestRequestHandlerResponseCache(t *testing.T) {
+	var backendRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := backendRequests.Add(1)
+		if r.URL.Path == "/no-store" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		fmt.Fprintf(w, "path=%s; request=%d", r.URL.Path, n)
+	}))
+	defer ts.Close()
+
+	cfgStr := fmt.Sprintf(`
+unauthorized_user:
+  url_prefix: %s
+  response_cache_ttl: 1h`, ts.URL)
+	cfgOrigP := authConfigData.Load()
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(method, path, responseExpected string, backendRequestsExpected int32) {
+		t.Helper()
+
+		r, err := http.NewRequest(method, "http://some-host.com"+path, nil)
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := w.getResponse()
+		response = strings.ReplaceAll(response, "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		if response != responseExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", response, responseExpected)
+		}
+		if n := backendRequests.Load(); n != backendRequestsExpected {
+			t.Fatalf("unexpected number of backend requests; got %d; want %d", n, backendRequestsExpected)
+		}
+	}
+
+	// the second GET request must be served from cache
+	f(http.MethodGet, "/foo", "statusCode=200\npath=/foo; request=1", 1)
+	f(http.MethodGet, "/foo", "statusCode=200\npath=/foo; request=1", 1)
+
+	// requests with other path aren't served from cache
+	f(http.MethodGet, "/bar", "statusCode=200\npath=/bar; request=2", 2)
+
+	// POST requests aren't cached
+	f(http.MethodPost, "/foo", "statusCode=200\npath=/foo; request=3", 3)
+
+	// responses with Cache-Control: no-store aren't cached
+	f(http.MethodGet, "/no-store", "statusCode=200\nCache-Control: no-store\npath=/no-store; request=4", 4)
+	f(http.MethodGet, "/no-store", "statusCode=200\nCache-Control: no-store\npath=/no-store; request=5", 5)
+}
+
+func TestRequestHandlerResponseCacheSrcHeaders(t *testing.T) {
+	var backendRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := backendRequests.Add(1)
+		fmt.Fprintf(w, "path=%s; accountID=%s; request=%d", r.URL.Path, r.Header.Get("AccountID"), n)
+	}))
+	defer ts.Close()
+
+	cfgStr := fmt.Sprintf(`
+unauthorized_user:
+  response_cache_ttl: 1h
+  url_map:
+  - src_paths: ["/foo"]
+    src_headers: ["TenantID: a"]
+    url_prefix: %s/a
+  - src_paths: ["/foo"]
+    src_headers: ["TenantID: b"]
+    url_prefix: %s/b`, ts.URL, ts.URL)
+	cfgOrigP := authConfigData.Load()
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(tenantID, accountID, responseExpected string, backendRequestsExpected int32) {
+		t.Helper()
+
+		r, err := http.NewRequest(http.MethodGet, "http://some-host.com/foo", nil)
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+		r.Header.Set("TenantID", tenantID)
+		if accountID != "" {
+			r.Header.Set("AccountID", accountID)
+		}
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := w.getResponse()
+		response = strings.ReplaceAll(response, "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		if response != responseExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", response, responseExpected)
+		}
+		if n := backendRequests.Load(); n != backendRequestsExpected {
+			t.Fatalf("unexpected number of backend requests; got %d; want %d", n, backendRequestsExpected)
+		}
+	}
+
+	// requests with distinct src_headers values are routed and cached separately
+	f("a", "", "statusCode=200\npath=/a/foo; accountID=; request=1", 1)
+	f("b", "", "statusCode=200\npath=/b/foo; accountID=; request=2", 2)
+	f("a", "", "statusCode=200\npath=/a/foo; accountID=; request=1", 2)
+	f("b", "", "statusCode=200\npath=/b/foo; accountID=; request=2", 2)
+
+	// requests with distinct VictoriaLogs tenants are cached separately
+	f("a", "1", "statusCode=200\npath=/a/foo; accountID=1; request=3", 3)
+	f("a", "2", "statusCode=200\npath=/a/foo; accountID=2; request=4", 4)
+	f("a", "1", "statusCode=200\npath=/a/foo; accountID=1; request=3", 4)
+}
+
+func TestRequestHandlerMirror(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "path=%s", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	mirrorRequestsCh := make(chan string, 10)
+	tsMirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read request body: %s", err)
+		}
+		mirrorRequestsCh <- fmt.Sprintf("%s %s %q", r.Method, r.URL.RequestURI(), body)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer tsMirror.Close()
+
+	cfgStr := fmt.Sprintf(`
+unauthorized_user:
+  url_map:
+  - src_paths: ["/select/.*"]
+    url_prefix: %s
+    mirror_url: %s/shadow
+  - src_paths: ["/insert/.*"]
+    url_prefix: %s
+    mirror_url: %s/shadow
+    mirror_percent: 0`, ts.URL, tsMirror.URL, ts.URL, tsMirror.URL)
+	cfgOrigP := authConfigData.Load()
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(method, path, body, responseExpected, mirrorRequestExpected string) {
+		t.Helper()
+
+		r, err := http.NewRequest(method, "http://some-host.com"+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := w.getResponse()
+		response = strings.ReplaceAll(response, "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		if response != responseExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", response, responseExpected)
+		}
+
+		if mirrorRequestExpected == "" {
+			select {
+			case mr := <-mirrorRequestsCh:
+				t.Fatalf("unexpected mirrored request: %s", mr)
+			case <-time.After(100 * time.Millisecond):
+			}
+			return
+		}
+		select {
+		case mr := <-mirrorRequestsCh:
+			if mr != mirrorRequestExpected {
+				t.Fatalf("unexpected mirrored request\ngot\n%s\nwant\n%s", mr, mirrorRequestExpected)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout when waiting for mirrored request")
+		}
+	}
+
+	// requests are mirrored with the original body; mirror errors do not affect the response
+	f(http.MethodGet, "/select/foo?bar=baz", "", "statusCode=200\npath=/select/foo", `GET /shadow/select/foo?bar=baz ""`)
+	f(http.MethodPost, "/select/bar", "some data", "statusCode=200\npath=/select/bar", `POST /shadow/select/bar "some data"`)
+
+	// requests aren't mirrored with zero mirror_percent
+	f(http.MethodPost, "/insert/foo", "some data", "statusCode=200\npath=/insert/foo", "")
+}
+
+func TestURLPrefixGetLogsTenant(t *testing.T) {
+	f := func(logsTenant, tenant, resultExpected string) {
+		t.Helper()
+
+		up := &URLPrefix{
+			logsTenant: logsTenant,
+		}
+		lt, err := up.getLogsTenant(tenant)
+		if err != nil {
+			if resultExpected != "error" {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			return
+		}
+		if resultExpected == "error" {
+			t.Fatalf("expecting non-nil error")
+		}
+		if lt == nil {
+			if resultExpected != "" {
+				t.Fatalf("unexpected nil tenant; want %q", resultExpected)
+			}
+			return
+		}
+		h := http.Header{}
+		setLogsTenantHeaders(h, lt)
+		result := h.Get("AccountID") + ":" + h.Get("ProjectID")
+		if result != resultExpected {
+			t.Fatalf("unexpected tenant; got %q; want %q", result, resultExpected)
+		}
+	}
+
+	// missing logs_tenant
+	f("", "", "")
+	f("", "12", "")
+
+	// static logs_tenant
+	f("12", "", "12:0")
+	f("12:34", "56", "12:34")
+
+	// logs_tenant from JWT
+	f("{{tenant}}", "12:34", "12:34")
+	f("{{tenant}}", "56", "56:0")
+	f("{{tenant}}", "", "error")
+	f("{{tenant}}", "foo", "error")
+}
+
+func TestRequestsRateLimiter(t *testing.T) {
+	if rl := newRequestsRateLimiter(0); !rl.tryRegister() {
+		t.Fatalf("rate limiter with zero limit must accept all the requests")
+	}
+
+	rl := newRequestsRateLimiter(2)
+	currentSecond := fasttime.UnixTimestamp()
+	if !rl.tryRegister() || !rl.tryRegister() {
+		t.Fatalf("rate limiter must accept requests below the limit")
+	}
+	if rl.tryRegister() && fasttime.UnixTimestamp() == currentSecond {
+		t.Fatalf("rate limiter must reject requests above the limit")
+	}
+}
+
 type fakeResponseWriter struct {
 	h http.Header
 
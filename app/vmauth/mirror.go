@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var (
+	mirrorMaxRequestBodySize = flagutil.NewBytes("mirror.maxRequestBodySize", 1024*1024, "The maximum request body size, which can be mirrored to `mirror_url`. "+
+		"Requests with bigger bodies aren't mirrored. See https://docs.victoriametrics.com/victoriametrics/vmauth/#request-mirroring")
+	mirrorMaxConcurrentRequests = flag.Int("mirror.maxConcurrentRequests", 100, "The maximum number of concurrent requests vmauth can send to `mirror_url` backends. "+
+		"Other mirrored requests are dropped. See https://docs.victoriametrics.com/victoriametrics/vmauth/#request-mirroring")
+)
+
+var (
+	mirrorRequests          = metrics.NewCounter(`vmauth_mirror_requests_total`)
+	mirrorRequestErrors     = metrics.NewCounter(`vmauth_mirror_request_errors_total`)
+	mirrorSkippedBodyErrors = metrics.NewCounter(`vmauth_mirror_requests_skipped_total{reason="body_not_cached"}`)
+	mirrorSkippedLimit      = metrics.NewCounter(`vmauth_mirror_requests_skipped_total{reason="concurrency_limit"}`)
+)
+
+// mirrorLogger limits the rate of logged mirroring errors, since they may occur on every request when mirror_url is unavailable.
+//
+// All the errors are counted at vmauth_mirror_request_errors_total metric.
+var mirrorLogger = logger.WithThrottler("vmauth_mirror", 5*time.Second)
+
+var (
+	mirrorConcurrencyCh     chan struct{}
+	mirrorConcurrencyChOnce sync.Once
+)
+
+func getMirrorConcurrencyCh() chan struct{} {
+	mirrorConcurrencyChOnce.Do(func() {
+		n := *mirrorMaxConcurrentRequests
+		if n <= 0 {
+			n = 1
+		}
+		mirrorConcurrencyCh = make(chan struct{}, n)
+	})
+	return mirrorConcurrencyCh
+}
+
+// shouldMirror returns true if the request routed to up must be mirrored to up.mirrorURL.
+func (up *URLPrefix) shouldMirror() bool {
+	if up.mirrorURL == nil {
+		return false
+	}
+	if up.mirrorPercent >= 100 {
+		return true
+	}
+	return rand.Float64()*100 < up.mirrorPercent
+}
+
+// mirrorBody is a request body, which caches the data read from r for sending it to mirror_url.
+type mirrorBody struct {
+	r io.ReadCloser
+
+	maxBodySize int
+
+	mu sync.Mutex
+
+	// buf contains the data read from r.
+	buf []byte
+
+	// tooBig is set to true when more than maxBodySize bytes are read from r.
+	tooBig bool
+
+	// complete is set to true when r has been read until io.EOF.
+	complete bool
+}
+
+func newMirrorBody(r io.ReadCloser, maxBodySize int) *mirrorBody {
+	if r == nil || r == http.NoBody {
+		// This is a request without request body
+		return &mirrorBody{
+			r:        http.NoBody,
+			complete: true,
+		}
+	}
+	return &mirrorBody{
+		r:           r,
+		maxBodySize: maxBodySize,
+	}
+}
+
+// Read implements io.Reader interface.
+func (mb *mirrorBody) Read(p []byte) (int, error) {
+	n, err := mb.r.Read(p)
+
+	mb.mu.Lock()
+	if !mb.tooBig {
+		if len(mb.buf)+n > mb.maxBodySize {
+			mb.tooBig = true
+			mb.buf = nil
+		} else {
+			mb.buf = append(mb.buf, p[:n]...)
+		}
+	}
+	if err == io.EOF {
+		mb.complete = true
+	}
+	mb.mu.Unlock()
+
+	return n, err
+}
+
+// Close implements io.Closer interface.
+func (mb *mirrorBody) Close() error {
+	return mb.r.Close()
+}
+
+// getBody returns the complete body read from mb.
+//
+// false is returned if the body cannot be mirrored, since it is too big or it wasn't read completely.
+func (mb *mirrorBody) getBody() ([]byte, bool) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if mb.tooBig || !mb.complete {
+		return nil, false
+	}
+	return mb.buf, true
+}
+
+// mirrorRequest asynchronously sends a copy of r with the given body to mirrorURL.
+//
+// The response from mirrorURL is ignored.
+func mirrorRequest(r *http.Request, mb *mirrorBody, mirrorURL *url.URL, hc HeadersConf, ui *UserInfo) {
+	body, ok := mb.getBody()
+	if !ok {
+		mirrorSkippedBodyErrors.Inc()
+		return
+	}
+
+	ch := getMirrorConcurrencyCh()
+	select {
+	case ch <- struct{}{}:
+	default:
+		mirrorSkippedLimit.Inc()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *responseTimeout)
+	req := sanitizeRequestHeaders(r).WithContext(ctx)
+	req.URL = mirrorURL
+	req.Header.Set("User-Agent", "vmauth")
+	updateHeadersByConfig(req.Header, hc.RequestHeaders)
+	if hc.KeepOriginalHost == nil || !*hc.KeepOriginalHost {
+		req.Host = mirrorURL.Host
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	mirrorRequests.Inc()
+	go func() {
+		defer func() {
+			cancel()
+			<-ch
+		}()
+		if err := sendMirrorRequest(req, ui); err != nil {
+			mirrorRequestErrors.Inc()
+			mirrorLogger.Warnf("cannot mirror the request to %s: %s", mirrorURL, err)
+		}
+	}()
+}
+
+func sendMirrorRequest(req *http.Request, ui *UserInfo) error {
+	res, err := ui.rt.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return fmt.Errorf("cannot read response body: %w", err)
+	}
+	if res.StatusCode/100 == 5 {
+		return fmt.Errorf("unexpected response status code=%d", res.StatusCode)
+	}
+	return nil
+}
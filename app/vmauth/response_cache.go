@@ -0,0 +1,199 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+)
+
+var (
+	responseCacheMaxSizePerUser = flagutil.NewBytes("responseCacheMaxSizePerUser", 32*1024*1024, "The maximum size of cached responses per each user with enabled response_cache_ttl option. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmauth/#response-caching")
+	responseCacheMaxEntrySize = flagutil.NewBytes("responseCacheMaxEntrySize", 1024*1024, "The maximum size of a single response, which can be cached for users with enabled response_cache_ttl option. "+
+		"Bigger responses aren't cached. See https://docs.victoriametrics.com/victoriametrics/vmauth/#response-caching")
+)
+
+// responseCache caches responses for GET requests for the given ttl.
+type responseCache struct {
+	ttl time.Duration
+
+	// keyHeaders contains the names of request headers, which may affect the response.
+	keyHeaders []string
+
+	mu        sync.Mutex
+	m         map[string]*cachedResponse
+	sizeBytes int
+}
+
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	deadline   time.Time
+}
+
+func (cr *cachedResponse) size(key string) int {
+	return len(key) + len(cr.body)
+}
+
+func newResponseCache(ttl time.Duration, ui *UserInfo) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		keyHeaders: getResponseCacheKeyHeaders(ui),
+		m:          make(map[string]*cachedResponse),
+	}
+}
+
+// getResponseCacheKeyHeaders returns sorted names of request headers, which may change the response for ui.
+//
+// These are headers used for routing via src_headers and headers with VictoriaLogs tenant.
+func getResponseCacheKeyHeaders(ui *UserInfo) []string {
+	names := []string{"AccountID", "ProjectID"}
+	for _, e := range ui.URLMaps {
+		for _, h := range e.SrcHeaders {
+			names = append(names, http.CanonicalHeaderKey(h.Name))
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// isCacheableRequest returns true if the response for r can be cached.
+func isCacheableRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	return !hasNoCacheDirective(r.Header)
+}
+
+func isCacheableResponse(res *http.Response) bool {
+	if res.StatusCode != http.StatusOK {
+		return false
+	}
+	return !hasNoCacheDirective(res.Header)
+}
+
+func hasNoCacheDirective(h http.Header) bool {
+	cc := h.Get("Cache-Control")
+	return strings.Contains(cc, "no-cache") || strings.Contains(cc, "no-store")
+}
+
+// getKey returns the cache key for r sent by the given tenant and routed to the url_map entry with the given routeIdx.
+//
+// Responses for the same path and query args may differ depending on the selected route, the tenant, the requested host,
+// the accepted encoding and the headers from rc.keyHeaders.
+func (rc *responseCache) getKey(r *http.Request, routeIdx int, tenant string) string {
+	var b []byte
+	b = strconv.AppendInt(b, int64(routeIdx), 10)
+	b = append(b, '\n')
+	b = append(b, tenant...)
+	b = append(b, '\n')
+	b = append(b, r.Host...)
+	b = append(b, '\n')
+	b = append(b, r.URL.RequestURI()...)
+	b = append(b, '\n')
+	b = append(b, r.Header.Get("Accept-Encoding")...)
+	for _, name := range rc.keyHeaders {
+		b = append(b, '\n')
+		b = append(b, name...)
+		b = append(b, ':')
+		b = append(b, strings.Join(r.Header.Values(name), ",")...)
+	}
+	return string(b)
+}
+
+// tryServe writes the cached response for the given key to w.
+//
+// It returns false if there is no cached response for the key.
+func (rc *responseCache) tryServe(w http.ResponseWriter, key string) bool {
+	rc.mu.Lock()
+	cr := rc.m[key]
+	if cr != nil && time.Now().After(cr.deadline) {
+		rc.sizeBytes -= cr.size(key)
+		delete(rc.m, key)
+		cr = nil
+	}
+	rc.mu.Unlock()
+
+	if cr == nil {
+		return false
+	}
+	copyHeader(w.Header(), cr.header)
+	w.WriteHeader(cr.statusCode)
+	_, _ = w.Write(cr.body)
+	return true
+}
+
+func (rc *responseCache) put(key string, statusCode int, header http.Header, body []byte) {
+	cr := &cachedResponse{
+		statusCode: statusCode,
+		header:     header,
+		body:       body,
+		deadline:   time.Now().Add(rc.ttl),
+	}
+	maxSize := responseCacheMaxSizePerUser.IntN()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if crOld := rc.m[key]; crOld != nil {
+		rc.sizeBytes -= crOld.size(key)
+		delete(rc.m, key)
+	}
+	if rc.sizeBytes+cr.size(key) > maxSize {
+		rc.removeExpiredLocked()
+		if rc.sizeBytes+cr.size(key) > maxSize {
+			// There is no space for the response.
+			return
+		}
+	}
+	rc.m[key] = cr
+	rc.sizeBytes += cr.size(key)
+}
+
+func (rc *responseCache) removeExpiredLocked() {
+	now := time.Now()
+	for key, cr := range rc.m {
+		if now.After(cr.deadline) {
+			rc.sizeBytes -= cr.size(key)
+			delete(rc.m, key)
+		}
+	}
+}
+
+func (rc *responseCache) getSizeBytes() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.sizeBytes
+}
+
+// cachingWriter writes data to w and collects it in buf
+// until the collected data size exceeds maxSize.
+type cachingWriter struct {
+	w       io.Writer
+	maxSize int
+
+	buf      []byte
+	overflow bool
+}
+
+// Write implements io.Writer interface.
+func (cw *cachingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if !cw.overflow {
+		if len(cw.buf)+n > cw.maxSize {
+			cw.overflow = true
+			cw.buf = nil
+		} else {
+			cw.buf = append(cw.buf, p[:n]...)
+		}
+	}
+	return n, err
+}
@@ -74,6 +74,21 @@ func (ui *UserInfo) getURLPrefixAndHeaders(u *url.URL, host string, h http.Heade
 	return nil, HeadersConf{}
 }
 
+// getRouteIndex returns the index of the url_map entry with the given up.
+//
+// It returns -1 for the url_prefix and -2 for the default_url.
+func (ui *UserInfo) getRouteIndex(up *URLPrefix) int {
+	for i := range ui.URLMaps {
+		if ui.URLMaps[i].URLPrefix == up {
+			return i
+		}
+	}
+	if up == ui.URLPrefix {
+		return -1
+	}
+	return -2
+}
+
 func matchAnyRegex(rs []*Regex, s string) bool {
 	if len(rs) == 0 {
 		return true
@@ -40,6 +40,8 @@ var (
 	origin            = flag.String("origin", "", "Optional origin directory on the remote storage with old backup for server-side copying when performing full backup. This speeds up full backups")
 	concurrency       = flag.Int("concurrency", 10, "The number of concurrent workers. Higher concurrency may reduce backup duration")
 	maxBytesPerSecond = flagutil.NewBytes("maxBytesPerSecond", 0, "The maximum upload speed. There is no limit if it is set to 0")
+	verify            = flag.Bool("verify", false, "Whether to verify the existing backup at -dst against the snapshot set via -snapshotName instead of creating a backup. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmbackup/#backup-verification")
 )
 
 func main() {
@@ -65,6 +67,15 @@ func main() {
 	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/2055
 	deleteSnapshot := func() {}
 
+	if *verify {
+		if len(*snapshotName) == 0 {
+			logger.Fatalf("-snapshotName must be set when -verify is set")
+		}
+		if len(*snapshotCreateURL) > 0 {
+			logger.Fatalf("-snapshot.createURL cannot be set when -verify is set, since the backup must be verified against the snapshot it has been made from")
+		}
+	}
+
 	if len(*snapshotCreateURL) > 0 {
 		// create net/url object
 		createURL, err := url.Parse(*snapshotCreateURL)
@@ -110,10 +121,16 @@ func main() {
 	go httpserver.Serve(listenAddrs, nil, httpserver.ServeOptions{})
 
 	pushmetrics.Init()
-	err := makeBackup(ctx)
-	deleteSnapshot()
-	if err != nil {
-		logger.Fatalf("cannot create backup: %s", err)
+	if *verify {
+		if err := verifyBackup(ctx); err != nil {
+			logger.Fatalf("cannot verify backup: %s", err)
+		}
+	} else {
+		err := makeBackup(ctx)
+		deleteSnapshot()
+		if err != nil {
+			logger.Fatalf("cannot create backup: %s", err)
+		}
 	}
 	pushmetrics.Stop()
 
@@ -155,11 +172,16 @@ func makeBackup(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		encryptionKey, err := actions.GetEncryptionKey()
+		if err != nil {
+			return err
+		}
 		a := &actions.Backup{
-			Concurrency: *concurrency,
-			Src:         srcFS,
-			Dst:         dstFS,
-			Origin:      originFS,
+			Concurrency:   *concurrency,
+			Src:           srcFS,
+			Dst:           dstFS,
+			Origin:        originFS,
+			EncryptionKey: encryptionKey,
 		}
 		if err := a.Run(); err != nil {
 			return err
@@ -171,6 +193,33 @@ func makeBackup(ctx context.Context) error {
 	return nil
 }
 
+func verifyBackup(ctx context.Context) error {
+	srcFS, err := newSrcFS()
+	if err != nil {
+		return err
+	}
+	dstFS, err := newDstFS(ctx)
+	if err != nil {
+		return err
+	}
+	encryptionKeys, err := actions.LoadEncryptionKeys()
+	if err != nil {
+		return err
+	}
+	a := &actions.Verify{
+		Concurrency:    *concurrency,
+		Src:            srcFS,
+		Dst:            dstFS,
+		EncryptionKeys: encryptionKeys,
+	}
+	if err := a.Run(ctx); err != nil {
+		return err
+	}
+	srcFS.MustStop()
+	dstFS.MustStop()
+	return nil
+}
+
 func usage() {
 	const s = `
 vmbackup performs backups for VictoriaMetrics data from instant snapshots to gcs, s3, azblob
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/barpool"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/elasticsearch"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+type esProcessor struct {
+	src *elasticsearch.Client
+	dst *vlogs.Importer
+
+	filter         elasticsearch.Filter
+	timeField      string
+	msgField       string
+	checkpointFile string
+	isVerbose      bool
+}
+
+func (ep *esProcessor) run(ctx context.Context) error {
+	checkpoint, err := readCheckpoint(ep.checkpointFile)
+	if err != nil {
+		return err
+	}
+	if checkpoint != "" {
+		log.Printf("resuming the migration from the checkpoint %q stored at %q; documents with timestamps close to the checkpoint may be imported twice", checkpoint, ep.checkpointFile)
+		// Documents with the checkpoint timestamp are imported again, since some of them may be left unimported.
+		// See https://docs.victoriametrics.com/victoriametrics/vmctl/elasticsearch/#resuming-the-migration
+		ep.filter.TimeStart = checkpoint
+	}
+
+	count, err := ep.src.Count(ctx, ep.filter)
+	if err != nil {
+		return fmt.Errorf("cannot count documents to migrate: %s", err)
+	}
+	if count == 0 {
+		log.Println("found no documents to migrate")
+		return nil
+	}
+	question := fmt.Sprintf("Found %d documents to migrate. Continue?", count)
+	if !prompt(question) {
+		return nil
+	}
+
+	bar := barpool.AddWithTemplate(fmt.Sprintf(barTpl, "Processing documents"), count)
+	if err := barpool.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		barpool.Stop()
+		log.Print(ep.dst.Stats())
+	}()
+
+	err = ep.src.Scroll(ctx, ep.filter, func(docs []elasticsearch.Document) error {
+		rows := make([]vlogs.Row, 0, len(docs))
+		for i := range docs {
+			d := &docs[i]
+			row, err := d.ToRow(ep.timeField, ep.msgField)
+			if err != nil {
+				return fmt.Errorf("cannot convert document %q from index %q: %s", d.ID, d.Index, err)
+			}
+			rows = append(rows, row)
+		}
+		if err := ep.dst.Import(ctx, rows); err != nil {
+			return err
+		}
+		bar.Add(len(docs))
+		if ep.isVerbose {
+			log.Printf("imported %d documents with timestamps from %s to %s", len(rows), formatTimestamp(rows[0].Timestamp), formatTimestamp(rows[len(rows)-1].Timestamp))
+		}
+		// Documents are sorted by timestamp, so the last imported document has the biggest timestamp.
+		return writeCheckpoint(ep.checkpointFile, formatTimestamp(rows[len(rows)-1].Timestamp))
+	})
+	if err != nil {
+		return fmt.Errorf("migration failed: %s", err)
+	}
+	log.Println("Import finished!")
+	return nil
+}
+
+func formatTimestamp(ts int64) string {
+	return time.Unix(0, ts).UTC().Format(time.RFC3339Nano)
+}
+
+// readCheckpoint returns the checkpoint stored at path.
+//
+// It returns an empty string if path is empty or the file at path doesn't exist.
+func readCheckpoint(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("cannot read checkpoint file: %w", err)
+	}
+	checkpoint := strings.TrimSpace(string(data))
+	if checkpoint == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(time.RFC3339Nano, checkpoint); err != nil {
+		return "", fmt.Errorf("cannot parse checkpoint %q stored at %q: %w", checkpoint, path, err)
+	}
+	return checkpoint, nil
+}
+
+// writeCheckpoint atomically stores checkpoint at path.
+func writeCheckpoint(path, checkpoint string) error {
+	if path == "" {
+		return nil
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmpPath, []byte(checkpoint+"\n"), 0644); err != nil {
+		return fmt.Errorf("cannot write checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("cannot update checkpoint file: %w", err)
+	}
+	return nil
+}
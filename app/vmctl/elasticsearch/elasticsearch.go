@@ -0,0 +1,362 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+// Config contains list of params to configure the Client
+type Config struct {
+	// Addr of Elasticsearch or OpenSearch
+	Addr string
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+	// User name for basic auth
+	User string
+	// Password for basic auth
+	Password string
+	// Index is the name or the pattern of indices to migrate documents from
+	Index string
+	// Query is an optional query in Elasticsearch Query DSL format for selecting documents to migrate
+	Query string
+	// TimeField is the name of document field with the timestamp.
+	// Documents are migrated in the order of this field
+	TimeField string
+	// BatchSize is the number of documents to fetch per each request
+	BatchSize int
+	// ScrollTimeout is how long to keep the search context alive between requests
+	ScrollTimeout time.Duration
+}
+
+// Filter contains time range filter for documents to migrate
+type Filter struct {
+	// TimeStart is an optional lower bound for TimeField in RFC3339 format
+	TimeStart string
+	// TimeEnd is an optional upper bound for TimeField in RFC3339 format
+	TimeEnd string
+}
+
+// Client is an HTTP client for fetching documents
+// from Elasticsearch or OpenSearch via scroll API.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/paginate-search-results.html#scroll-search-results
+type Client struct {
+	addr          string
+	c             *http.Client
+	user          string
+	password      string
+	index         string
+	query         json.RawMessage
+	timeField     string
+	batchSize     int
+	scrollTimeout string
+}
+
+// NewClient creates new Client for the given cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Index == "" {
+		return nil, fmt.Errorf("index cannot be empty")
+	}
+	if cfg.TimeField == "" {
+		return nil, fmt.Errorf("time field cannot be empty")
+	}
+	var query json.RawMessage
+	if cfg.Query != "" {
+		if !json.Valid([]byte(cfg.Query)) {
+			return nil, fmt.Errorf("query must be a valid JSON object; got %q", cfg.Query)
+		}
+		query = json.RawMessage(cfg.Query)
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.ScrollTimeout <= 0 {
+		cfg.ScrollTimeout = 5 * time.Minute
+	}
+	c := &http.Client{}
+	if cfg.Transport != nil {
+		c.Transport = cfg.Transport
+	}
+	return &Client{
+		addr:          strings.TrimRight(cfg.Addr, "/"),
+		c:             c,
+		user:          cfg.User,
+		password:      cfg.Password,
+		index:         cfg.Index,
+		query:         query,
+		timeField:     cfg.TimeField,
+		batchSize:     cfg.BatchSize,
+		scrollTimeout: fmt.Sprintf("%ds", int(cfg.ScrollTimeout.Seconds())),
+	}, nil
+}
+
+// Document is a single document fetched from Elasticsearch
+type Document struct {
+	Index  string         `json:"_index"`
+	ID     string         `json:"_id"`
+	Source map[string]any `json:"_source"`
+}
+
+type searchResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []Document `json:"hits"`
+	} `json:"hits"`
+}
+
+// Count returns the number of documents matching the configured query and the given filter.
+func (c *Client) Count(ctx context.Context, filter Filter) (int, error) {
+	body := map[string]any{
+		"query": c.getQuery(filter),
+	}
+	path := fmt.Sprintf("/%s/_count", url.PathEscape(c.index))
+	var resp struct {
+		Count int `json:"count"`
+	}
+	if err := c.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// Scroll fetches all the documents matching the configured query and the given filter
+// in the ascending order of the time field and calls f for every fetched batch.
+func (c *Client) Scroll(ctx context.Context, filter Filter, f func(docs []Document) error) error {
+	body := map[string]any{
+		"size":  c.batchSize,
+		"query": c.getQuery(filter),
+		"sort": []any{
+			map[string]any{
+				c.timeField: map[string]any{
+					"order": "asc",
+				},
+			},
+		},
+	}
+	path := fmt.Sprintf("/%s/_search?scroll=%s", url.PathEscape(c.index), c.scrollTimeout)
+	var resp searchResponse
+	if err := c.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return err
+	}
+	scrollID := resp.ScrollID
+	defer func() {
+		if scrollID != "" {
+			c.clearScroll(scrollID)
+		}
+	}()
+
+	for len(resp.Hits.Hits) > 0 {
+		if err := f(resp.Hits.Hits); err != nil {
+			return err
+		}
+		body := map[string]any{
+			"scroll":    c.scrollTimeout,
+			"scroll_id": scrollID,
+		}
+		resp = searchResponse{}
+		if err := c.do(ctx, http.MethodPost, "/_search/scroll", body, &resp); err != nil {
+			return err
+		}
+		if resp.ScrollID != "" {
+			scrollID = resp.ScrollID
+		}
+	}
+	return nil
+}
+
+func (c *Client) clearScroll(scrollID string) {
+	body := map[string]any{
+		"scroll_id": []string{scrollID},
+	}
+	// Use background context, since ctx may be already canceled.
+	// Errors are ignored, since the search context is freed by Elasticsearch after scroll timeout anyway.
+	_ = c.do(context.Background(), http.MethodDelete, "/_search/scroll", body, nil)
+}
+
+func (c *Client) getQuery(filter Filter) any {
+	var filters []any
+	if c.query != nil {
+		filters = append(filters, c.query)
+	}
+	if filter.TimeStart != "" || filter.TimeEnd != "" {
+		r := make(map[string]any)
+		if filter.TimeStart != "" {
+			r["gte"] = filter.TimeStart
+		}
+		if filter.TimeEnd != "" {
+			r["lte"] = filter.TimeEnd
+		}
+		filters = append(filters, map[string]any{
+			"range": map[string]any{
+				c.timeField: r,
+			},
+		})
+	}
+	if len(filters) == 0 {
+		return map[string]any{
+			"match_all": map[string]any{},
+		}
+	}
+	return map[string]any{
+		"bool": map[string]any{
+			"filter": filters,
+		},
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, dst any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("cannot marshal request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %w", c.addr, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %q failed: %w", c.addr+path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response code %d for %q: %s", resp.StatusCode, c.addr+path, respBody)
+	}
+	if dst == nil {
+		return nil
+	}
+	d := json.NewDecoder(resp.Body)
+	// Preserve numbers as is, since they may exceed float64 precision.
+	d.UseNumber()
+	if err := d.Decode(dst); err != nil {
+		return fmt.Errorf("cannot decode response from %q: %w", c.addr+path, err)
+	}
+	return nil
+}
+
+// ToRow converts d to VictoriaLogs row.
+//
+// The value of timeField is used as the row timestamp, while the value of msgField is used as `_msg` field.
+// Nested objects are flattened into fields with dot-separated names, while arrays are stored as JSON strings.
+func (d *Document) ToRow(timeField, msgField string) (vlogs.Row, error) {
+	var row vlogs.Row
+	fields := flattenFields(nil, "", d.Source)
+	ts := int64(-1)
+	for i := range fields {
+		f := &fields[i].field
+		switch f.Name {
+		case timeField:
+			t, err := ParseTimestamp(fields[i].value)
+			if err != nil {
+				return row, fmt.Errorf("cannot parse %q field: %w", timeField, err)
+			}
+			ts = t
+			continue
+		case msgField:
+			f.Name = "_msg"
+		}
+		row.Fields = append(row.Fields, *f)
+	}
+	if ts < 0 {
+		return row, fmt.Errorf("missing %q field", timeField)
+	}
+	row.Timestamp = ts
+	return row, nil
+}
+
+type flatField struct {
+	field vlogs.Field
+	value any
+}
+
+func flattenFields(dst []flatField, prefix string, m map[string]any) []flatField {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		var s string
+		switch v := m[k].(type) {
+		case nil:
+			continue
+		case map[string]any:
+			dst = flattenFields(dst, name, v)
+			continue
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		case bool:
+			s = strconv.FormatBool(v)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			s = string(data)
+		}
+		dst = append(dst, flatField{
+			field: vlogs.Field{
+				Name:  name,
+				Value: s,
+			},
+			value: m[k],
+		})
+	}
+	return dst
+}
+
+// ParseTimestamp parses timestamp v obtained from Elasticsearch document and returns it in nanoseconds.
+//
+// Numeric timestamps are treated as milliseconds since the Unix epoch,
+// which is the default format for numeric date fields in Elasticsearch.
+func ParseTimestamp(v any) (int64, error) {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			tm, err := time.Parse(layout, t)
+			if err == nil {
+				return tm.UnixNano(), nil
+			}
+		}
+		return 0, fmt.Errorf("unsupported timestamp format %q; supported formats: RFC3339 or milliseconds since the Unix epoch", t)
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n * 1e6, nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse timestamp %q: %w", t, err)
+		}
+		return int64(f * 1e6), nil
+	default:
+		return 0, fmt.Errorf("unsupported timestamp type %T", v)
+	}
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	// Elasticsearch treats timestamps without timezone as UTC
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
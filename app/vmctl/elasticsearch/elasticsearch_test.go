@@ -0,0 +1,178 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	f := func(v any, resultExpected int64) {
+		t.Helper()
+
+		result, err := ParseTimestamp(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %v; got %d; want %d", v, result, resultExpected)
+		}
+	}
+
+	f("2024-01-02T03:04:05Z", 1704164645000000000)
+	f("2024-01-02T03:04:05.123456789Z", 1704164645123456789)
+	f("2024-01-02T05:04:05.123+02:00", 1704164645123000000)
+	f("2024-01-02T03:04:05.123", 1704164645123000000)
+	f("2024-01-02", 1704153600000000000)
+	f(json.Number("1704164645123"), 1704164645123000000)
+	f(json.Number("1.5"), 1500000)
+}
+
+func TestParseTimestampFailure(t *testing.T) {
+	f := func(v any) {
+		t.Helper()
+
+		if _, err := ParseTimestamp(v); err == nil {
+			t.Fatalf("expecting non-nil error for %v", v)
+		}
+	}
+
+	f("")
+	f("foobar")
+	f(json.Number("foo"))
+	f(true)
+	f(nil)
+}
+
+func TestDocumentToRow(t *testing.T) {
+	f := func(source string, rowExpected vlogs.Row) {
+		t.Helper()
+
+		var d Document
+		dec := json.NewDecoder(strings.NewReader(source))
+		dec.UseNumber()
+		if err := dec.Decode(&d.Source); err != nil {
+			t.Fatalf("cannot decode source: %s", err)
+		}
+		row, err := d.ToRow("@timestamp", "message")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(row, rowExpected) {
+			t.Fatalf("unexpected row\ngot\n%+v\nwant\n%+v", row, rowExpected)
+		}
+	}
+
+	f(`{"@timestamp":"2024-01-02T03:04:05Z","message":"foo bar"}`, vlogs.Row{
+		Timestamp: 1704164645000000000,
+		Fields: []vlogs.Field{
+			{Name: "_msg", Value: "foo bar"},
+		},
+	})
+
+	// nested objects, arrays, numbers, booleans and nulls
+	f(`{"@timestamp":1704164645123,"message":"foo","host":{"name":"host-1","ip":["10.0.0.1","10.0.0.2"]},"status":200,"ok":true,"err":null}`, vlogs.Row{
+		Timestamp: 1704164645123000000,
+		Fields: []vlogs.Field{
+			{Name: "host.ip", Value: `["10.0.0.1","10.0.0.2"]`},
+			{Name: "host.name", Value: "host-1"},
+			{Name: "_msg", Value: "foo"},
+			{Name: "ok", Value: "true"},
+			{Name: "status", Value: "200"},
+		},
+	})
+}
+
+func TestDocumentToRowFailure(t *testing.T) {
+	f := func(source string) {
+		t.Helper()
+
+		var d Document
+		if err := json.Unmarshal([]byte(source), &d.Source); err != nil {
+			t.Fatalf("cannot decode source: %s", err)
+		}
+		if _, err := d.ToRow("@timestamp", "message"); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// missing timestamp
+	f(`{"message":"foo"}`)
+
+	// invalid timestamp
+	f(`{"@timestamp":"yesterday","message":"foo"}`)
+}
+
+func TestClientScroll(t *testing.T) {
+	pages := []string{
+		`{"_scroll_id":"s1","hits":{"hits":[{"_index":"logs","_id":"1","_source":{"@timestamp":"2024-01-02T03:04:05Z"}},{"_index":"logs","_id":"2","_source":{"@timestamp":"2024-01-02T03:04:06Z"}}]}}`,
+		`{"_scroll_id":"s2","hits":{"hits":[{"_index":"logs","_id":"3","_source":{"@timestamp":"2024-01-02T03:04:07Z"}}]}}`,
+		`{"_scroll_id":"s2","hits":{"hits":[]}}`,
+	}
+	var requests []string
+	var scrollCleared bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodDelete {
+			scrollCleared = true
+			return
+		}
+		requests = append(requests, r.URL.RequestURI()+" "+string(body))
+		if len(requests) > len(pages) {
+			t.Errorf("unexpected request %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, pages[len(requests)-1])
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{
+		Addr:          srv.URL,
+		Index:         "logs-*",
+		Query:         `{"term":{"service":"api"}}`,
+		TimeField:     "@timestamp",
+		BatchSize:     2,
+		ScrollTimeout: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+	var ids []string
+	filter := Filter{
+		TimeStart: "2024-01-01T00:00:00Z",
+	}
+	err = c.Scroll(context.Background(), filter, func(docs []Document) error {
+		for _, d := range docs {
+			ids = append(ids, d.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Fatalf("unexpected document ids: %q", ids)
+	}
+	if !scrollCleared {
+		t.Fatalf("expecting the scroll to be cleared")
+	}
+
+	requestsExpected := []string{
+		`/logs-%2A/_search?scroll=60s {"query":{"bool":{"filter":[{"term":{"service":"api"}},{"range":{"@timestamp":{"gte":"2024-01-01T00:00:00Z"}}}]}},"size":2,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		`/_search/scroll {"scroll":"60s","scroll_id":"s1"}`,
+		`/_search/scroll {"scroll":"60s","scroll_id":"s2"}`,
+	}
+	if !reflect.DeepEqual(requests, requestsExpected) {
+		t.Fatalf("unexpected requests\ngot\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(requestsExpected, "\n"))
+	}
+}
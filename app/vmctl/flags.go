@@ -774,6 +774,305 @@ var (
 	}
 )
 
+const (
+	vlAddr               = "vl-addr"
+	vlUser               = "vl-user"
+	vlPassword           = "vl-password"
+	vlAccountID          = "vl-account-id"
+	vlProjectID          = "vl-project-id"
+	vlCompress           = "vl-compress"
+	vlCertFile           = "vl-cert-file"
+	vlKeyFile            = "vl-key-file"
+	vlCAFile             = "vl-CA-file"
+	vlServerName         = "vl-server-name"
+	vlInsecureSkipVerify = "vl-insecure-skip-verify"
+	vlBackoffRetries     = "vl-backoff-retries"
+	vlBackoffFactor      = "vl-backoff-factor"
+	vlBackoffMinDuration = "vl-backoff-min-duration"
+)
+
+var (
+	vlFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  vlAddr,
+			Value: "http://localhost:9428",
+			Usage: "VictoriaLogs address to perform import requests. \n" +
+				"Please note, that vmctl performs initial readiness check for the given address by checking /health endpoint.",
+		},
+		&cli.StringFlag{
+			Name:    vlUser,
+			Usage:   "VictoriaLogs username for basic auth",
+			EnvVars: []string{"VL_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    vlPassword,
+			Usage:   "VictoriaLogs password for basic auth",
+			EnvVars: []string{"VL_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:  vlAccountID,
+			Usage: "AccountID of the tenant to import logs into. See https://docs.victoriametrics.com/victorialogs/#multitenancy",
+		},
+		&cli.StringFlag{
+			Name:  vlProjectID,
+			Usage: "ProjectID of the tenant to import logs into. See https://docs.victoriametrics.com/victorialogs/#multitenancy",
+		},
+		&cli.BoolFlag{
+			Name:  vlCompress,
+			Value: true,
+			Usage: "Whether to apply gzip compression to import requests",
+		},
+		&cli.StringFlag{
+			Name:  vlCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--vl-addr'",
+		},
+		&cli.StringFlag{
+			Name:  vlKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--vl-addr'",
+		},
+		&cli.StringFlag{
+			Name:  vlCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--vl-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  vlServerName,
+			Usage: "Optional TLS server name to use for connections to '--vl-addr'. By default, the server name from '--vl-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  vlInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--vl-addr'",
+			Value: false,
+		},
+		&cli.IntFlag{
+			Name:  vlBackoffRetries,
+			Value: 10,
+			Usage: "How many import retries to perform before giving up.",
+		},
+		&cli.Float64Flag{
+			Name:  vlBackoffFactor,
+			Value: 1.8,
+			Usage: "Factor to multiply the base duration after each failed import retry. Must be greater than 1.0",
+		},
+		&cli.DurationFlag{
+			Name:  vlBackoffMinDuration,
+			Value: time.Second * 2,
+			Usage: "Minimum duration to wait before the first import retry. Each subsequent import retry will be multiplied by the '--vl-backoff-factor'.",
+		},
+	}
+)
+
+const (
+	esAddr               = "es-addr"
+	esUser               = "es-user"
+	esPassword           = "es-password"
+	esIndex              = "es-index"
+	esQuery              = "es-query"
+	esTimeField          = "es-time-field"
+	esMsgField           = "es-msg-field"
+	esStreamFields       = "es-stream-fields"
+	esBatchSize          = "es-batch-size"
+	esScrollTimeout      = "es-scroll-timeout"
+	esFilterTimeStart    = "es-filter-time-start"
+	esFilterTimeEnd      = "es-filter-time-end"
+	esCheckpointFile     = "es-checkpoint-file"
+	esCertFile           = "es-cert-file"
+	esKeyFile            = "es-key-file"
+	esCAFile             = "es-CA-file"
+	esServerName         = "es-server-name"
+	esInsecureSkipVerify = "es-insecure-skip-verify"
+)
+
+var (
+	esFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  esAddr,
+			Value: "http://localhost:9200",
+			Usage: "Elasticsearch or OpenSearch server addr",
+		},
+		&cli.StringFlag{
+			Name:    esUser,
+			Usage:   "Elasticsearch username for basic auth",
+			EnvVars: []string{"ES_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    esPassword,
+			Usage:   "Elasticsearch password for basic auth",
+			EnvVars: []string{"ES_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:     esIndex,
+			Usage:    "Elasticsearch index, alias, data stream or index pattern to migrate documents from. E.g. 'logs-*'",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  esQuery,
+			Usage: "Optional query in Elasticsearch Query DSL format for selecting documents to migrate. E.g. '{\"term\":{\"service.name\":\"api\"}}'. By default, all the documents are migrated",
+		},
+		&cli.StringFlag{
+			Name:  esTimeField,
+			Value: "@timestamp",
+			Usage: "The name of document field with the timestamp. It is stored as _time field in VictoriaLogs. See https://docs.victoriametrics.com/victorialogs/keyconcepts/#time-field",
+		},
+		&cli.StringFlag{
+			Name:  esMsgField,
+			Value: "message",
+			Usage: "The name of document field with the log message. It is stored as _msg field in VictoriaLogs. See https://docs.victoriametrics.com/victorialogs/keyconcepts/#message-field",
+		},
+		&cli.StringSliceFlag{
+			Name: esStreamFields,
+			Usage: "Document fields to use as log stream fields in VictoriaLogs. E.g. 'host.name,service.name'. Nested fields must be referred via dot-separated names. " +
+				"See https://docs.victoriametrics.com/victorialogs/keyconcepts/#stream-fields",
+		},
+		&cli.IntFlag{
+			Name:  esBatchSize,
+			Value: 1000,
+			Usage: "How many documents to fetch from Elasticsearch per each request. Every fetched batch is imported into VictoriaLogs with a single request",
+		},
+		&cli.DurationFlag{
+			Name:  esScrollTimeout,
+			Value: 5 * time.Minute,
+			Usage: "How long Elasticsearch must keep the search context alive between requests for the next batch of documents",
+		},
+		&cli.StringFlag{
+			Name:  esFilterTimeStart,
+			Usage: "The time filter in RFC3339 format to select documents with timestamp equal or higher than provided value. E.g. '2020-01-01T20:07:00Z'",
+		},
+		&cli.StringFlag{
+			Name:  esFilterTimeEnd,
+			Usage: "The time filter in RFC3339 format to select documents with timestamp equal or lower than provided value. E.g. '2020-01-01T20:07:00Z'",
+		},
+		&cli.StringFlag{
+			Name: esCheckpointFile,
+			Usage: "Optional path to file for storing the migration progress. If the file contains the progress of the previous interrupted migration, " +
+				"then the migration is resumed from the stored position. Documents near the stored position may be imported twice after resuming the migration. " +
+				"See https://docs.victoriametrics.com/victoriametrics/vmctl/elasticsearch/#resuming-the-migration",
+		},
+		&cli.StringFlag{
+			Name:  esCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--es-addr'",
+		},
+		&cli.StringFlag{
+			Name:  esKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--es-addr'",
+		},
+		&cli.StringFlag{
+			Name:  esCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--es-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  esServerName,
+			Usage: "Optional TLS server name to use for connections to '--es-addr'. By default, the server name from '--es-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  esInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--es-addr'",
+			Value: false,
+		},
+	}
+)
+
+const (
+	lokiAddr               = "loki-addr"
+	lokiUser               = "loki-user"
+	lokiPassword           = "loki-password"
+	lokiTenantID           = "loki-tenant-id"
+	lokiQuery              = "loki-query"
+	lokiConcurrency        = "loki-concurrency"
+	lokiBatchSize          = "loki-batch-size"
+	lokiFilterTimeStart    = "loki-filter-time-start"
+	lokiFilterTimeEnd      = "loki-filter-time-end"
+	lokiFilterTimeReverse  = "loki-filter-time-reverse"
+	lokiStepInterval       = "loki-step-interval"
+	lokiCertFile           = "loki-cert-file"
+	lokiKeyFile            = "loki-key-file"
+	lokiCAFile             = "loki-CA-file"
+	lokiServerName         = "loki-server-name"
+	lokiInsecureSkipVerify = "loki-insecure-skip-verify"
+)
+
+var (
+	lokiFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:     lokiAddr,
+			Usage:    "Loki address to fetch logs from. E.g. 'http://localhost:3100'",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    lokiUser,
+			Usage:   "Loki username for basic auth",
+			EnvVars: []string{"LOKI_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    lokiPassword,
+			Usage:   "Loki password for basic auth",
+			EnvVars: []string{"LOKI_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:  lokiTenantID,
+			Usage: "Optional Loki tenant to migrate logs from. It is sent in X-Scope-OrgID request header",
+		},
+		&cli.StringFlag{
+			Name:     lokiQuery,
+			Usage:    "LogQL stream selector for logs to migrate. E.g. '{job=~\".+\"}'",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  lokiConcurrency,
+			Usage: "Number of concurrently processed time ranges",
+			Value: 1,
+		},
+		&cli.IntFlag{
+			Name:  lokiBatchSize,
+			Value: 5000,
+			Usage: "The maximum number of log entries to fetch from Loki per each request. It mustn't exceed max_entries_limit_per_query option at Loki",
+		},
+		&cli.TimestampFlag{
+			Name:     lokiFilterTimeStart,
+			Usage:    "The time filter in RFC3339 format to select logs with timestamp equal or higher than provided value. E.g. '2020-01-01T20:07:00Z'",
+			Layout:   time.RFC3339,
+			Required: true,
+		},
+		&cli.TimestampFlag{
+			Name:   lokiFilterTimeEnd,
+			Usage:  "The time filter in RFC3339 format to select logs with timestamp lower than provided value. E.g. '2020-01-01T20:07:00Z'. By default, the current time is used",
+			Layout: time.RFC3339,
+		},
+		&cli.StringFlag{
+			Name: lokiStepInterval,
+			Usage: fmt.Sprintf("The time interval to split the migration into steps. For example, to migrate 1y of data with '--%s=month' vmctl will process 12 separate time ranges. "+
+				"To reverse the order use '--%s'. Valid values are '%s','%s','%s','%s','%s'.",
+				lokiStepInterval, lokiFilterTimeReverse, stepper.StepMonth, stepper.StepWeek, stepper.StepDay, stepper.StepHour, stepper.StepMinute),
+			Value: stepper.StepDay,
+		},
+		&cli.BoolFlag{
+			Name:  lokiFilterTimeReverse,
+			Usage: fmt.Sprintf("Whether to reverse the order of time intervals split by '--%s' cmd-line flag. When set, the migration will start from the newest to the oldest logs.", lokiStepInterval),
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  lokiCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--loki-addr'",
+		},
+		&cli.StringFlag{
+			Name:  lokiKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--loki-addr'",
+		},
+		&cli.StringFlag{
+			Name:  lokiCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--loki-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  lokiServerName,
+			Usage: "Optional TLS server name to use for connections to '--loki-addr'. By default, the server name from '--loki-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  lokiInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--loki-addr'",
+			Value: false,
+		},
+	}
+)
+
 func mergeFlags(flags ...[]cli.Flag) []cli.Flag {
 	var result []cli.Flag
 	for _, f := range flags {
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/barpool"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/loki"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/stepper"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+type lokiProcessor struct {
+	src *loki.Client
+	dst *vlogs.Importer
+
+	timeStart   time.Time
+	timeEnd     time.Time
+	chunk       string
+	timeReverse bool
+
+	cc        int
+	isVerbose bool
+}
+
+func (lp *lokiProcessor) run(ctx context.Context) error {
+	if lp.cc < 1 {
+		lp.cc = 1
+	}
+
+	ranges, err := stepper.SplitDateRange(lp.timeStart, lp.timeEnd, lp.chunk, lp.timeReverse)
+	if err != nil {
+		return fmt.Errorf("failed to create date ranges for the given time filters: %v", err)
+	}
+
+	question := fmt.Sprintf("Selected time range %q - %q will be split into %d ranges according to %q step. Continue?",
+		lp.timeStart.String(), lp.timeEnd.String(), len(ranges), lp.chunk)
+	if !prompt(question) {
+		return nil
+	}
+
+	bar := barpool.AddWithTemplate(fmt.Sprintf(barTpl, "Processing ranges"), len(ranges))
+	if err := barpool.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		barpool.Stop()
+		log.Print(lp.dst.Stats())
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rangeC := make(chan []time.Time)
+	errCh := make(chan error, lp.cc)
+
+	var wg sync.WaitGroup
+	wg.Add(lp.cc)
+	for i := 0; i < lp.cc; i++ {
+		go func() {
+			defer wg.Done()
+			for r := range rangeC {
+				if err := lp.do(ctx, r[0], r[1]); err != nil {
+					errCh <- fmt.Errorf("failed to migrate logs for time range %s - %s: %s", r[0].Format(time.RFC3339), r[1].Format(time.RFC3339), err)
+					cancel()
+					return
+				}
+				bar.Increment()
+			}
+		}()
+	}
+
+	var rangesErr error
+	for _, r := range ranges {
+		select {
+		case rangesErr = <-errCh:
+		case rangeC <- r:
+			continue
+		}
+		break
+	}
+	close(rangeC)
+	wg.Wait()
+	close(errCh)
+
+	if rangesErr != nil {
+		return fmt.Errorf("import process failed: %s", rangesErr)
+	}
+	for err := range errCh {
+		return fmt.Errorf("import process failed: %s", err)
+	}
+	log.Println("Import finished!")
+	return nil
+}
+
+func (lp *lokiProcessor) do(ctx context.Context, start, end time.Time) error {
+	return lp.src.Fetch(ctx, start, end, func(rows []vlogs.Row) error {
+		if err := lp.dst.Import(ctx, rows); err != nil {
+			return err
+		}
+		if lp.isVerbose {
+			log.Printf("imported %d log entries with timestamps from %s to %s", len(rows), formatTimestamp(rows[0].Timestamp), formatTimestamp(rows[len(rows)-1].Timestamp))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,327 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+// Config contains list of params to configure the Client
+type Config struct {
+	// Addr of Loki
+	Addr string
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+	// User name for basic auth
+	User string
+	// Password for basic auth
+	Password string
+	// TenantID is an optional tenant to migrate logs from.
+	// It is sent in X-Scope-OrgID header
+	TenantID string
+	// Query is LogQL stream selector for logs to migrate
+	Query string
+	// BatchSize is the maximum number of log entries to fetch per each request
+	BatchSize int
+}
+
+// Client is an HTTP client for fetching logs
+// from Loki via query_range API with pagination.
+// See https://grafana.com/docs/loki/latest/reference/loki-http-api/#query-logs-within-a-range-of-time
+type Client struct {
+	addr      string
+	c         *http.Client
+	user      string
+	password  string
+	tenantID  string
+	query     string
+	batchSize int
+}
+
+// NewClient creates new Client for the given cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 5000
+	}
+	c := &http.Client{}
+	if cfg.Transport != nil {
+		c.Transport = cfg.Transport
+	}
+	return &Client{
+		addr:      strings.TrimRight(cfg.Addr, "/"),
+		c:         c,
+		user:      cfg.User,
+		password:  cfg.Password,
+		tenantID:  cfg.TenantID,
+		query:     cfg.Query,
+		batchSize: cfg.BatchSize,
+	}, nil
+}
+
+// Labels returns names of stream labels for logs on the given time range.
+func (c *Client) Labels(ctx context.Context, start, end time.Time) ([]string, error) {
+	args := url.Values{}
+	args.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	args.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	var resp struct {
+		Data []string `json:"data"`
+	}
+	if err := c.do(ctx, "/loki/api/v1/labels", args, &resp); err != nil {
+		return nil, err
+	}
+	labels := resp.Data[:0]
+	for _, label := range resp.Data {
+		// Skip internal labels such as __stream_shard__
+		if strings.HasPrefix(label, "__") {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+type queryRangeResponse struct {
+	Data struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string   `json:"stream"`
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+type entry struct {
+	ts     int64
+	labels map[string]string
+	line   string
+	meta   map[string]string
+}
+
+// Fetch fetches all the logs for the configured query on the time range [start, end)
+// in the ascending order of timestamps and calls f for every fetched batch.
+func (c *Client) Fetch(ctx context.Context, start, end time.Time, f func(rows []vlogs.Row) error) error {
+	startNs := start.UnixNano()
+	endNs := end.UnixNano()
+	// seen contains keys for entries with the timestamp equal to startNs,
+	// which have been already processed on the previous page.
+	// Loki returns entries with start timestamp on the next page, since start is inclusive.
+	var seen map[string]struct{}
+	for startNs < endNs {
+		entries, err := c.queryRange(ctx, startNs, endNs, c.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].ts < entries[j].ts
+		})
+
+		lastTs := entries[len(entries)-1].ts
+		isLastPage := len(entries) < c.batchSize
+		isSingleTs := !isLastPage && entries[0].ts == lastTs
+		if isSingleTs {
+			// All the entries at the page have the same timestamp, so the remaining entries with this timestamp
+			// cannot be fetched by moving the start of the time range. Fetch all of them at once.
+			entries, err = c.fetchTimestamp(ctx, lastTs)
+			if err != nil {
+				return err
+			}
+		}
+
+		seenNext := make(map[string]struct{})
+		rows := make([]vlogs.Row, 0, len(entries))
+		for _, e := range entries {
+			k := e.key()
+			if e.ts == startNs {
+				if _, ok := seen[k]; ok {
+					continue
+				}
+			}
+			if e.ts == lastTs {
+				seenNext[k] = struct{}{}
+			}
+			rows = append(rows, e.toRow())
+		}
+		if len(rows) > 0 {
+			if err := f(rows); err != nil {
+				return err
+			}
+		}
+
+		if isLastPage {
+			return nil
+		}
+		if isSingleTs {
+			// All the entries with lastTs have been processed.
+			startNs = lastTs + 1
+			seen = nil
+			continue
+		}
+		startNs = lastTs
+		seen = seenNext
+	}
+	return nil
+}
+
+// fetchTimestamp returns all the entries with the given ts.
+//
+// The limit for the number of returned entries is doubled until all the entries are returned.
+func (c *Client) fetchTimestamp(ctx context.Context, ts int64) ([]entry, error) {
+	limit := c.batchSize
+	for {
+		limit *= 2
+		entries, err := c.queryRange(ctx, ts, ts+1, limit)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch more than %d log entries with the timestamp %s; "+
+				"try increasing --loki-batch-size or max_entries_limit_per_query at Loki: %w", limit/2, time.Unix(0, ts).UTC().Format(time.RFC3339Nano), err)
+		}
+		if len(entries) < limit {
+			return entries, nil
+		}
+	}
+}
+
+func (c *Client) queryRange(ctx context.Context, startNs, endNs int64, limit int) ([]entry, error) {
+	args := url.Values{}
+	args.Set("query", c.query)
+	args.Set("start", strconv.FormatInt(startNs, 10))
+	args.Set("end", strconv.FormatInt(endNs, 10))
+	args.Set("limit", strconv.Itoa(limit))
+	args.Set("direction", "forward")
+	var resp queryRangeResponse
+	if err := c.do(ctx, "/loki/api/v1/query_range", args, &resp); err != nil {
+		return nil, err
+	}
+	if rt := resp.Data.ResultType; rt != "streams" {
+		return nil, fmt.Errorf("unexpected result type %q; want %q; make sure the query is a log query", rt, "streams")
+	}
+	var entries []entry
+	for _, s := range resp.Data.Result {
+		for _, v := range s.Values {
+			e, err := parseEntry(v)
+			if err != nil {
+				return nil, err
+			}
+			e.labels = s.Stream
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// parseEntry parses Loki entry in the format [ "<unix_epoch_nanoseconds>", "<log line>", {<structured metadata>} ].
+func parseEntry(v []json.RawMessage) (entry, error) {
+	var e entry
+	if len(v) < 2 {
+		return e, fmt.Errorf("unexpected number of items in entry; got %d; want at least 2", len(v))
+	}
+	var tsStr string
+	if err := json.Unmarshal(v[0], &tsStr); err != nil {
+		return e, fmt.Errorf("cannot parse entry timestamp %s: %w", v[0], err)
+	}
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return e, fmt.Errorf("cannot parse entry timestamp %q: %w", tsStr, err)
+	}
+	e.ts = ts
+	if err := json.Unmarshal(v[1], &e.line); err != nil {
+		return e, fmt.Errorf("cannot parse entry line %s: %w", v[1], err)
+	}
+	if len(v) > 2 {
+		if err := json.Unmarshal(v[2], &e.meta); err != nil {
+			return e, fmt.Errorf("cannot parse entry structured metadata %s: %w", v[2], err)
+		}
+	}
+	return e, nil
+}
+
+func (e *entry) key() string {
+	names := make([]string, 0, len(e.labels))
+	for name := range e.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte('=')
+		sb.WriteString(e.labels[name])
+		sb.WriteByte(',')
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(e.line)
+	return sb.String()
+}
+
+// toRow converts e to VictoriaLogs row.
+//
+// Stream labels and structured metadata are stored as log fields, while the log line is stored as `_msg` field.
+func (e *entry) toRow() vlogs.Row {
+	fields := make([]vlogs.Field, 0, len(e.labels)+len(e.meta)+1)
+	fields = appendSortedFields(fields, e.labels)
+	fields = appendSortedFields(fields, e.meta)
+	fields = append(fields, vlogs.Field{
+		Name:  "_msg",
+		Value: e.line,
+	})
+	return vlogs.Row{
+		Timestamp: e.ts,
+		Fields:    fields,
+	}
+}
+
+func appendSortedFields(dst []vlogs.Field, m map[string]string) []vlogs.Field {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dst = append(dst, vlogs.Field{
+			Name:  name,
+			Value: m[name],
+		})
+	}
+	return dst
+}
+
+func (c *Client) do(ctx context.Context, path string, args url.Values, dst any) error {
+	u := c.addr + path + "?" + args.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %w", c.addr, err)
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	if c.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", c.tenantID)
+	}
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %q failed: %w", c.addr+path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response code %d for %q: %s", resp.StatusCode, c.addr+path, respBody)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("cannot decode response from %q: %w", c.addr+path, err)
+	}
+	return nil
+}
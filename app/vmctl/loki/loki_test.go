@@ -0,0 +1,176 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+type testEntry struct {
+	stream string
+	ts     int64
+	line   string
+}
+
+// newTestServer returns Loki-like server, which serves entries via query_range API.
+func newTestServer(t *testing.T, entries []testEntry) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		start, _ := strconv.ParseInt(q.Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(q.Get("end"), 10, 64)
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		var selected []testEntry
+		for _, e := range entries {
+			if e.ts >= start && e.ts < end {
+				selected = append(selected, e)
+			}
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].ts < selected[j].ts
+		})
+		if len(selected) > limit {
+			selected = selected[:limit]
+		}
+
+		type stream struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		}
+		streams := make(map[string]*stream)
+		var result []*stream
+		for _, e := range selected {
+			s := streams[e.stream]
+			if s == nil {
+				s = &stream{
+					Stream: map[string]string{"job": e.stream},
+				}
+				streams[e.stream] = s
+				result = append(result, s)
+			}
+			s.Values = append(s.Values, []string{strconv.FormatInt(e.ts, 10), e.line})
+		}
+		resp := map[string]any{
+			"status": "success",
+			"data": map[string]any{
+				"resultType": "streams",
+				"result":     result,
+			},
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("cannot encode response: %s", err)
+		}
+	}))
+}
+
+func TestClientFetch(t *testing.T) {
+	f := func(entries []testEntry, batchSize int) {
+		t.Helper()
+
+		srv := newTestServer(t, entries)
+		defer srv.Close()
+
+		c, err := NewClient(Config{
+			Addr:      srv.URL,
+			Query:     `{job=~".+"}`,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			t.Fatalf("cannot create client: %s", err)
+		}
+		var result []string
+		err = c.Fetch(context.Background(), time.Unix(0, 0), time.Unix(100, 0), func(rows []vlogs.Row) error {
+			for _, r := range rows {
+				result = append(result, fmt.Sprintf("%d %s %s", r.Timestamp, r.Fields[0].Value, r.Fields[1].Value))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var resultExpected []string
+		for _, e := range entries {
+			resultExpected = append(resultExpected, fmt.Sprintf("%d %s %s", e.ts, e.stream, e.line))
+		}
+		sort.Strings(result)
+		sort.Strings(resultExpected)
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%q\nwant\n%q", result, resultExpected)
+		}
+	}
+
+	entries := []testEntry{
+		{"a", 1e9, "foo"},
+		{"b", 1e9, "bar"},
+		{"a", 2e9, "baz"},
+		{"b", 3e9, "qux"},
+		{"a", 3e9, "quux"},
+		{"a", 3e9, "corge"},
+		{"b", 4e9, "grault"},
+	}
+
+	// all the entries fit a single page
+	f(entries, 100)
+
+	// multiple pages with entries sharing timestamps at page boundaries
+	f(entries, 3)
+	f(entries, 4)
+
+	// no entries
+	f(nil, 2)
+
+	// more than batchSize entries share the same timestamp
+	var sameTsEntries []testEntry
+	sameTsEntries = append(sameTsEntries, testEntry{"a", 1e9, "first"})
+	for i := 0; i < 10; i++ {
+		sameTsEntries = append(sameTsEntries, testEntry{"b", 2e9, fmt.Sprintf("line%d", i)})
+	}
+	sameTsEntries = append(sameTsEntries, testEntry{"a", 3e9, "last"})
+	f(sameTsEntries, 3)
+	f(sameTsEntries, 5)
+	f(sameTsEntries[1:11], 4)
+}
+
+func TestEntryToRow(t *testing.T) {
+	e := &entry{
+		ts: 123,
+		labels: map[string]string{
+			"job":      "api",
+			"instance": "host-1",
+		},
+		meta: map[string]string{
+			"trace_id": "abc",
+		},
+		line: "foo bar",
+	}
+	rowExpected := vlogs.Row{
+		Timestamp: 123,
+		Fields: []vlogs.Field{
+			{Name: "instance", Value: "host-1"},
+			{Name: "job", Value: "api"},
+			{Name: "trace_id", Value: "abc"},
+			{Name: "_msg", Value: "foo bar"},
+		},
+	}
+	row := e.toRow()
+	if !reflect.DeepEqual(row, rowExpected) {
+		t.Fatalf("unexpected row\ngot\n%+v\nwant\n%+v", row, rowExpected)
+	}
+}
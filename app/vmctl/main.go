@@ -21,9 +21,12 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/remoteread"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/elasticsearch"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/influx"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/loki"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/opentsdb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/prometheus"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vm"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputil"
@@ -386,6 +389,142 @@ func main() {
 					return p.run(ctx)
 				},
 			},
+			{
+				Name:   "es-to-vl",
+				Usage:  "Migrate logs from Elasticsearch or OpenSearch to VictoriaLogs",
+				Flags:  mergeFlags(globalFlags, esFlags, vlFlags),
+				Before: beforeFn,
+				Action: func(c *cli.Context) error {
+					fmt.Println("Elasticsearch to VictoriaLogs import mode")
+
+					addr := c.String(esAddr)
+					if err := httputil.CheckURL(addr); err != nil {
+						return fmt.Errorf("invalid -%s: %w", esAddr, err)
+					}
+
+					// create Transport with given TLS config
+					certFile := c.String(esCertFile)
+					keyFile := c.String(esKeyFile)
+					caFile := c.String(esCAFile)
+					serverName := c.String(esServerName)
+					insecureSkipVerify := c.Bool(esInsecureSkipVerify)
+
+					tr, err := promauth.NewTLSTransport(certFile, keyFile, caFile, serverName, insecureSkipVerify, "vmctl_elasticsearch")
+					if err != nil {
+						return fmt.Errorf("failed to create transport for -%s=%q: %s", esAddr, addr, err)
+					}
+
+					esClient, err := elasticsearch.NewClient(elasticsearch.Config{
+						Addr:          addr,
+						Transport:     tr,
+						User:          c.String(esUser),
+						Password:      c.String(esPassword),
+						Index:         c.String(esIndex),
+						Query:         c.String(esQuery),
+						TimeField:     c.String(esTimeField),
+						BatchSize:     c.Int(esBatchSize),
+						ScrollTimeout: c.Duration(esScrollTimeout),
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create elasticsearch client: %s", err)
+					}
+
+					vlCfg, err := initConfigVL(c, c.StringSlice(esStreamFields))
+					if err != nil {
+						return fmt.Errorf("failed to init VictoriaLogs configuration: %s", err)
+					}
+					vlImporter, err := vlogs.NewImporter(vlCfg)
+					if err != nil {
+						return fmt.Errorf("failed to create VictoriaLogs importer: %s", err)
+					}
+
+					ep := esProcessor{
+						src: esClient,
+						dst: vlImporter,
+						filter: elasticsearch.Filter{
+							TimeStart: c.String(esFilterTimeStart),
+							TimeEnd:   c.String(esFilterTimeEnd),
+						},
+						timeField:      c.String(esTimeField),
+						msgField:       c.String(esMsgField),
+						checkpointFile: c.String(esCheckpointFile),
+						isVerbose:      c.Bool(globalVerbose),
+					}
+					return ep.run(ctx)
+				},
+			},
+			{
+				Name:   "loki-to-vl",
+				Usage:  "Migrate logs from Loki to VictoriaLogs",
+				Flags:  mergeFlags(globalFlags, lokiFlags, vlFlags),
+				Before: beforeFn,
+				Action: func(c *cli.Context) error {
+					fmt.Println("Loki to VictoriaLogs import mode")
+
+					addr := c.String(lokiAddr)
+					if err := httputil.CheckURL(addr); err != nil {
+						return fmt.Errorf("invalid -%s: %w", lokiAddr, err)
+					}
+
+					// create Transport with given TLS config
+					certFile := c.String(lokiCertFile)
+					keyFile := c.String(lokiKeyFile)
+					caFile := c.String(lokiCAFile)
+					serverName := c.String(lokiServerName)
+					insecureSkipVerify := c.Bool(lokiInsecureSkipVerify)
+
+					tr, err := promauth.NewTLSTransport(certFile, keyFile, caFile, serverName, insecureSkipVerify, "vmctl_loki")
+					if err != nil {
+						return fmt.Errorf("failed to create transport for -%s=%q: %s", lokiAddr, addr, err)
+					}
+
+					lokiClient, err := loki.NewClient(loki.Config{
+						Addr:      addr,
+						Transport: tr,
+						User:      c.String(lokiUser),
+						Password:  c.String(lokiPassword),
+						TenantID:  c.String(lokiTenantID),
+						Query:     c.String(lokiQuery),
+						BatchSize: c.Int(lokiBatchSize),
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create loki client: %s", err)
+					}
+
+					timeStart := *c.Timestamp(lokiFilterTimeStart)
+					timeEnd := time.Now().In(timeStart.Location())
+					if t := c.Timestamp(lokiFilterTimeEnd); t != nil {
+						timeEnd = *t
+					}
+
+					// Loki labels are used as log stream fields in VictoriaLogs.
+					streamFields, err := lokiClient.Labels(ctx, timeStart, timeEnd)
+					if err != nil {
+						return fmt.Errorf("failed to obtain labels from loki: %s", err)
+					}
+
+					vlCfg, err := initConfigVL(c, streamFields)
+					if err != nil {
+						return fmt.Errorf("failed to init VictoriaLogs configuration: %s", err)
+					}
+					vlImporter, err := vlogs.NewImporter(vlCfg)
+					if err != nil {
+						return fmt.Errorf("failed to create VictoriaLogs importer: %s", err)
+					}
+
+					lp := lokiProcessor{
+						src:         lokiClient,
+						dst:         vlImporter,
+						timeStart:   timeStart,
+						timeEnd:     timeEnd,
+						chunk:       c.String(lokiStepInterval),
+						timeReverse: c.Bool(lokiFilterTimeReverse),
+						cc:          c.Int(lokiConcurrency),
+						isVerbose:   c.Bool(globalVerbose),
+					}
+					return lp.run(ctx)
+				},
+			},
 			{
 				Name:  "verify-block",
 				Usage: "Verifies exported block with VictoriaMetrics Native format",
@@ -487,3 +626,42 @@ func initConfigVM(c *cli.Context) (vm.Config, error) {
 		Backoff:            bf,
 	}, nil
 }
+
+func initConfigVL(c *cli.Context, streamFields []string) (vlogs.Config, error) {
+	addr := c.String(vlAddr)
+	if err := httputil.CheckURL(addr); err != nil {
+		return vlogs.Config{}, fmt.Errorf("invalid -%s: %w", vlAddr, err)
+	}
+
+	// create Transport with given TLS config
+	certFile := c.String(vlCertFile)
+	keyFile := c.String(vlKeyFile)
+	caFile := c.String(vlCAFile)
+	serverName := c.String(vlServerName)
+	insecureSkipVerify := c.Bool(vlInsecureSkipVerify)
+
+	tr, err := promauth.NewTLSTransport(certFile, keyFile, caFile, serverName, insecureSkipVerify, "vmctl_vlogs")
+	if err != nil {
+		return vlogs.Config{}, fmt.Errorf("failed to create transport for -%s=%q: %s", vlAddr, addr, err)
+	}
+
+	bfRetries := c.Int(vlBackoffRetries)
+	bfFactor := c.Float64(vlBackoffFactor)
+	bfMinDuration := c.Duration(vlBackoffMinDuration)
+	bf, err := backoff.New(bfRetries, bfFactor, bfMinDuration)
+	if err != nil {
+		return vlogs.Config{}, fmt.Errorf("failed to create backoff object: %s", err)
+	}
+
+	return vlogs.Config{
+		Addr:         addr,
+		Transport:    tr,
+		User:         c.String(vlUser),
+		Password:     c.String(vlPassword),
+		AccountID:    c.String(vlAccountID),
+		ProjectID:    c.String(vlProjectID),
+		StreamFields: streamFields,
+		Compress:     c.Bool(vlCompress),
+		Backoff:      bf,
+	}, nil
+}
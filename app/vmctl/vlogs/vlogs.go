@@ -0,0 +1,251 @@
+package vlogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/backoff"
+)
+
+// Config contains list of params to configure the Importer
+type Config struct {
+	// VictoriaLogs address to perform import requests
+	Addr string
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+	// User name for basic auth
+	User string
+	// Password for basic auth
+	Password string
+	// AccountID is tenant AccountID. Empty value means the default tenant
+	AccountID string
+	// ProjectID is tenant ProjectID. Empty value means the default tenant
+	ProjectID string
+	// StreamFields is the list of fields, which must be used as log stream fields.
+	// See https://docs.victoriametrics.com/victorialogs/keyconcepts/#stream-fields
+	StreamFields []string
+	// Whether to apply gzip compression
+	Compress bool
+	// Backoff defines backoff policy for retries
+	Backoff *backoff.Backoff
+}
+
+// Field is a single log field
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Row is a single log entry
+type Row struct {
+	// Timestamp is the log entry timestamp in nanoseconds
+	Timestamp int64
+	// Fields are the log entry fields. The `_msg` field contains the log message.
+	Fields []Field
+}
+
+// Importer performs insertion of log entries into VictoriaLogs
+// via JSON stream API.
+// See https://docs.victoriametrics.com/victorialogs/data-ingestion/#json-stream-api
+type Importer struct {
+	addr       string
+	client     *http.Client
+	importPath string
+	compress   bool
+	user       string
+	password   string
+	accountID  string
+	projectID  string
+	backoff    *backoff.Backoff
+
+	rows           atomic.Uint64
+	bytes          atomic.Uint64
+	requests       atomic.Uint64
+	retries        atomic.Uint64
+	importDuration atomic.Int64
+}
+
+// NewImporter creates new Importer for the given cfg.
+func NewImporter(cfg Config) (*Importer, error) {
+	if cfg.Backoff == nil {
+		return nil, fmt.Errorf("backoff policy must be set")
+	}
+	addr := strings.TrimRight(cfg.Addr, "/")
+	importPath := addr + "/insert/jsonline"
+	if len(cfg.StreamFields) > 0 {
+		importPath += "?_stream_fields=" + url.QueryEscape(strings.Join(cfg.StreamFields, ","))
+	}
+
+	client := &http.Client{}
+	if cfg.Transport != nil {
+		client.Transport = cfg.Transport
+	}
+
+	im := &Importer{
+		addr:       addr,
+		client:     client,
+		importPath: importPath,
+		compress:   cfg.Compress,
+		user:       cfg.User,
+		password:   cfg.Password,
+		accountID:  cfg.AccountID,
+		projectID:  cfg.ProjectID,
+		backoff:    cfg.Backoff,
+	}
+	if err := im.Ping(); err != nil {
+		return nil, fmt.Errorf("ping to %q failed: %s", addr, err)
+	}
+	return im, nil
+}
+
+// Ping checks the health of VictoriaLogs at im.addr
+func (im *Importer) Ping() error {
+	url := fmt.Sprintf("%s/health", im.addr)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %s", im.addr, err)
+	}
+	if im.user != "" {
+		req.SetBasicAuth(im.user, im.password)
+	}
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Import sends rows to VictoriaLogs.
+//
+// The import request is retried according to the configured backoff policy.
+func (im *Importer) Import(ctx context.Context, rows []Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	body, err := marshalRows(rows, im.compress)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	attempts, err := im.backoff.Retry(ctx, func() error {
+		return im.send(ctx, body)
+	})
+	im.retries.Add(attempts)
+	if err != nil {
+		return fmt.Errorf("import failed with %d retries: %w", attempts, err)
+	}
+	im.importDuration.Add(int64(time.Since(start)))
+	im.rows.Add(uint64(len(rows)))
+	im.bytes.Add(uint64(len(body)))
+	im.requests.Add(1)
+	return nil
+}
+
+func (im *Importer) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, im.importPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %s", im.addr, err)
+	}
+	if im.user != "" {
+		req.SetBasicAuth(im.user, im.password)
+	}
+	if im.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if im.accountID != "" {
+		req.Header.Set("AccountID", im.accountID)
+	}
+	if im.projectID != "" {
+		req.Header.Set("ProjectID", im.projectID)
+	}
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("import request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest {
+		return fmt.Errorf("%w: unexpected response code %d: %s", backoff.ErrBadRequest, resp.StatusCode, respBody)
+	}
+	return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, respBody)
+}
+
+func marshalRows(rows []Row, compress bool) ([]byte, error) {
+	var bb bytes.Buffer
+	var w io.Writer = &bb
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(&bb)
+		w = zw
+	}
+	var line []byte
+	for i := range rows {
+		line = rows[i].marshal(line[:0])
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return nil, fmt.Errorf("cannot write row: %w", err)
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("cannot compress rows: %w", err)
+		}
+	}
+	return bb.Bytes(), nil
+}
+
+// marshal appends JSON representation of r to dst and returns the result.
+func (r *Row) marshal(dst []byte) []byte {
+	dst = append(dst, `{"_time":`...)
+	dst = appendJSONString(dst, time.Unix(0, r.Timestamp).UTC().Format(time.RFC3339Nano))
+	for _, f := range r.Fields {
+		if f.Name == "_time" {
+			continue
+		}
+		dst = append(dst, ',')
+		dst = appendJSONString(dst, f.Name)
+		dst = append(dst, ':')
+		dst = appendJSONString(dst, f.Value)
+	}
+	dst = append(dst, '}')
+	return dst
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
+}
+
+// Stats returns im stats.
+func (im *Importer) Stats() string {
+	var s strings.Builder
+	rows := im.rows.Load()
+	bytes := im.bytes.Load()
+	importDuration := time.Duration(im.importDuration.Load())
+	fmt.Fprintf(&s, "VictoriaLogs importer stats:\n")
+	fmt.Fprintf(&s, "  time spent while importing: %v;\n", importDuration)
+	fmt.Fprintf(&s, "  total rows: %d;\n", rows)
+	if importDuration > 0 {
+		fmt.Fprintf(&s, "  rows/s: %.2f;\n", float64(rows)/importDuration.Seconds())
+	}
+	fmt.Fprintf(&s, "  total bytes: %d;\n", bytes)
+	fmt.Fprintf(&s, "  import requests: %d;\n", im.requests.Load())
+	fmt.Fprintf(&s, "  import requests retries: %d;", im.retries.Load())
+	return s.String()
+}
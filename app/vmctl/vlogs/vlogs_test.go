@@ -0,0 +1,40 @@
+package vlogs
+
+import (
+	"testing"
+)
+
+func TestMarshalRows(t *testing.T) {
+	f := func(rows []Row, resultExpected string) {
+		t.Helper()
+
+		result, err := marshalRows(rows, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	f(nil, "")
+
+	f([]Row{
+		{
+			Timestamp: 1704164645123456789,
+			Fields: []Field{
+				{Name: "_msg", Value: "foo \"bar\"\n"},
+				{Name: "host.name", Value: "host-1"},
+			},
+		},
+		{
+			Timestamp: 1704164646000000000,
+			Fields: []Field{
+				{Name: "_time", Value: "ignored"},
+				{Name: "_msg", Value: "baz"},
+			},
+		},
+	}, `{"_time":"2024-01-02T03:04:05.123456789Z","_msg":"foo \"bar\"\n","host.name":"host-1"}
+{"_time":"2024-01-02T03:04:06Z","_msg":"baz"}
+`)
+}
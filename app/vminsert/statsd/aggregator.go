@@ -0,0 +1,234 @@
+package statsd
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/valyala/histogram"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+)
+
+// aggregator aggregates statsd rows between flushes.
+//
+// Aggregated values are converted into Prometheus-like samples on flush:
+//
+//   - counters are converted into cumulative counters;
+//   - gauges keep the last value; deltas are applied to the last value;
+//   - sets are converted into the number of unique values seen since the previous flush;
+//   - timers, histograms and distributions are converted into summaries with `_count` and `_sum` cumulative counters
+//     plus `quantile` series calculated over values seen since the previous flush.
+type aggregator struct {
+	quantiles []float64
+
+	// idleTimeout is the duration in seconds after which the series without updates are dropped.
+	idleTimeout uint64
+
+	mu sync.Mutex
+	m  map[string]*aggrSeries
+}
+
+type aggrSeries struct {
+	metric string
+	tags   []prompb.Label
+	typ    string
+
+	lastUpdate uint64
+
+	// value contains the counter value for counters and the last value for gauges.
+	value float64
+
+	// count and sum are used for timers, histograms and distributions.
+	count float64
+	sum   float64
+	h     *histogram.Fast
+
+	// set contains unique values for sets.
+	set map[string]struct{}
+}
+
+// sample is a single aggregated sample generated on flush.
+type sample struct {
+	metric string
+	tags   []prompb.Label
+	value  float64
+}
+
+func newAggregator(quantiles []float64, idleTimeout uint64) *aggregator {
+	return &aggregator{
+		quantiles:   quantiles,
+		idleTimeout: idleTimeout,
+		m:           make(map[string]*aggrSeries),
+	}
+}
+
+// add adds rows to a.
+//
+// currentTime is the current unix timestamp in seconds.
+func (a *aggregator) add(rows []parser.Row, currentTime uint64) {
+	var tags []prompb.Label
+	var keyBuf []byte
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range rows {
+		r := &rows[i]
+		tags = tags[:0]
+		for _, t := range r.Tags {
+			tags = append(tags, prompb.Label{
+				Name:  t.Key,
+				Value: t.Value,
+			})
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			return tags[i].Name < tags[j].Name
+		})
+		keyBuf = marshalSeriesKey(keyBuf[:0], r.Type, r.Metric, tags)
+
+		s := a.m[string(keyBuf)]
+		if s == nil {
+			s = &aggrSeries{
+				metric: strings.Clone(r.Metric),
+				tags:   cloneLabels(tags),
+				typ:    r.Type,
+			}
+			a.m[string(keyBuf)] = s
+		}
+		s.lastUpdate = currentTime
+		s.update(r)
+	}
+}
+
+func (s *aggrSeries) update(r *parser.Row) {
+	switch s.typ {
+	case parser.TypeCounter:
+		for _, v := range r.Values {
+			s.value += v / r.SampleRate
+		}
+	case parser.TypeGauge:
+		for _, v := range r.Values {
+			if r.IsDelta {
+				s.value += v
+			} else {
+				s.value = v
+			}
+		}
+	case parser.TypeSet:
+		if s.set == nil {
+			s.set = make(map[string]struct{})
+		}
+		if _, ok := s.set[r.SetValue]; !ok {
+			s.set[strings.Clone(r.SetValue)] = struct{}{}
+		}
+	default:
+		// Timers, histograms and distributions
+		if s.h == nil {
+			s.h = histogram.GetFast()
+		}
+		for _, v := range r.Values {
+			s.count += 1 / r.SampleRate
+			s.sum += v / r.SampleRate
+			s.h.Update(v)
+		}
+	}
+}
+
+// flush returns aggregated samples and resets per-flush state.
+//
+// Series without updates for more than a.idleTimeout seconds are dropped.
+func (a *aggregator) flush(currentTime uint64) []sample {
+	var samples []sample
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for k, s := range a.m {
+		if currentTime > s.lastUpdate+a.idleTimeout {
+			if s.h != nil {
+				histogram.PutFast(s.h)
+			}
+			delete(a.m, k)
+			continue
+		}
+		samples = s.appendSamples(samples, a.quantiles)
+	}
+	return samples
+}
+
+func (s *aggrSeries) appendSamples(dst []sample, quantiles []float64) []sample {
+	switch s.typ {
+	case parser.TypeCounter, parser.TypeGauge:
+		dst = append(dst, sample{
+			metric: s.metric,
+			tags:   s.tags,
+			value:  s.value,
+		})
+	case parser.TypeSet:
+		dst = append(dst, sample{
+			metric: s.metric,
+			tags:   s.tags,
+			value:  float64(len(s.set)),
+		})
+		clear(s.set)
+	default:
+		dst = append(dst, sample{
+			metric: s.metric + "_count",
+			tags:   s.tags,
+			value:  s.count,
+		}, sample{
+			metric: s.metric + "_sum",
+			tags:   s.tags,
+			value:  s.sum,
+		})
+		for _, phi := range quantiles {
+			v := s.h.Quantile(phi)
+			if math.IsNaN(v) {
+				// No values since the previous flush
+				break
+			}
+			tags := append(s.tags[:len(s.tags):len(s.tags)], prompb.Label{
+				Name:  "quantile",
+				Value: strconv.FormatFloat(phi, 'g', -1, 64),
+			})
+			dst = append(dst, sample{
+				metric: s.metric,
+				tags:   tags,
+				value:  v,
+			})
+		}
+		s.h.Reset()
+	}
+	return dst
+}
+
+func marshalSeriesKey(dst []byte, typ, metric string, tags []prompb.Label) []byte {
+	dst = append(dst, typ...)
+	dst = append(dst, 0)
+	dst = append(dst, metric...)
+	for _, t := range tags {
+		dst = append(dst, 0)
+		dst = append(dst, t.Name...)
+		dst = append(dst, '=')
+		dst = append(dst, t.Value...)
+	}
+	return dst
+}
+
+func cloneLabels(labels []prompb.Label) []prompb.Label {
+	if len(labels) == 0 {
+		return nil
+	}
+	dst := make([]prompb.Label, len(labels))
+	for i, l := range labels {
+		dst[i] = prompb.Label{
+			Name:  strings.Clone(l.Name),
+			Value: strings.Clone(l.Value),
+		}
+	}
+	return dst
+}
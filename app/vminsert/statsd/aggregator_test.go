@@ -0,0 +1,108 @@
+package statsd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+)
+
+func TestAggregator(t *testing.T) {
+	a := newAggregator([]float64{0.5, 1}, 60)
+
+	f := func(data string, currentTime uint64, resultExpected string) {
+		t.Helper()
+
+		var rows parser.Rows
+		rows.Unmarshal(data)
+		a.add(rows.Rows, currentTime)
+
+		samples := a.flush(currentTime)
+		lines := make([]string, 0, len(samples))
+		for _, s := range samples {
+			var tags []string
+			for _, tag := range s.tags {
+				tags = append(tags, fmt.Sprintf("%s=%q", tag.Name, tag.Value))
+			}
+			lines = append(lines, fmt.Sprintf("%s{%s} %v", s.metric, strings.Join(tags, ","), s.value))
+		}
+		sort.Strings(lines)
+		result := strings.Join(lines, "\n")
+		if result != resultExpected {
+			t.Fatalf("unexpected result;\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	f(`
+requests:1|c|#env:prod,host:h1
+requests:2|c|@0.5|#host:h1,env:prod
+temperature:20|g
+temperature:-5|g
+users:u1|s
+users:u2|s
+users:u1|s
+latency:10:20|ms
+latency:30|ms|#path:/api
+`, 1000, `latency_count{path="/api"} 1
+latency_count{} 2
+latency_sum{path="/api"} 30
+latency_sum{} 30
+latency{path="/api",quantile="0.5"} 30
+latency{path="/api",quantile="1"} 30
+latency{quantile="0.5"} 20
+latency{quantile="1"} 20
+requests{env="prod",host="h1"} 5
+temperature{} 15
+users{} 2`)
+
+	// Counters and summary counts are cumulative, while sets and quantiles are reset on every flush
+	f(`
+requests:1|c|#env:prod,host:h1
+temperature:+1|g
+users:u3|s
+`, 1030, `latency_count{path="/api"} 1
+latency_count{} 2
+latency_sum{path="/api"} 30
+latency_sum{} 30
+requests{env="prod",host="h1"} 6
+temperature{} 16
+users{} 1`)
+
+	// Series without updates during the idle timeout are dropped
+	f(`
+latency:5|ms
+`, 1070, `latency_count{} 3
+latency_sum{} 35
+latency{quantile="0.5"} 5
+latency{quantile="1"} 5
+requests{env="prod",host="h1"} 6
+temperature{} 16
+users{} 0`)
+	f(``, 1200, ``)
+}
+
+func TestParseQuantiles(t *testing.T) {
+	f := func(s string, resultExpected []float64) {
+		t.Helper()
+
+		result, err := parseQuantiles(s)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result; got %v; want %v", result, resultExpected)
+		}
+	}
+
+	f("", nil)
+	f("0.5, 0.99,1", []float64{0.5, 0.99, 1})
+
+	for _, s := range []string{"foo", "1.5", "-0.1"} {
+		if _, err := parseQuantiles(s); err == nil {
+			t.Fatalf("expecting non-nil error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package statsd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/relabel"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd/stream"
+)
+
+var (
+	flushInterval = flag.Duration("statsd.flushInterval", 10*time.Second, "Interval for flushing aggregated statsd metrics received via -statsdListenAddr to the storage")
+	idleTimeout   = flag.Duration("statsd.idleTimeout", 5*time.Minute, "Aggregated statsd series without updates during this duration are no longer written to the storage. "+
+		"See -statsdListenAddr")
+	summaryQuantiles = flag.String("statsd.summaryQuantiles", "0.5,0.9,0.99", "Comma-separated list of quantiles to calculate for statsd timers, histograms and distributions "+
+		"received via -statsdListenAddr on every -statsd.flushInterval")
+)
+
+var (
+	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="statsd"}`)
+	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="statsd"}`)
+	flushErrors   = metrics.NewCounter(`vm_statsd_flush_errors_total`)
+)
+
+var (
+	aggr    *aggregator
+	stopCh  chan struct{}
+	flushWG sync.WaitGroup
+)
+
+// Init initializes statsd aggregation.
+//
+// It must be called before InsertHandler. MustStop must be called when statsd aggregation is no longer needed.
+func Init() {
+	quantiles, err := parseQuantiles(*summaryQuantiles)
+	if err != nil {
+		logger.Fatalf("cannot parse -statsd.summaryQuantiles=%q: %s", *summaryQuantiles, err)
+	}
+	if *flushInterval <= 0 {
+		logger.Fatalf("-statsd.flushInterval must be positive; got %s", *flushInterval)
+	}
+	aggr = newAggregator(quantiles, uint64(idleTimeout.Seconds()))
+	stopCh = make(chan struct{})
+	flushWG.Add(1)
+	go func() {
+		defer flushWG.Done()
+		runFlusher()
+	}()
+}
+
+// MustStop stops statsd aggregation and flushes the aggregated data to the storage.
+func MustStop() {
+	close(stopCh)
+	flushWG.Wait()
+}
+
+func runFlusher() {
+	t := time.NewTicker(*flushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stopCh:
+			flush()
+			return
+		case <-t.C:
+			flush()
+		}
+	}
+}
+
+func flush() {
+	currentTime := fasttime.UnixTimestamp()
+	samples := aggr.flush(currentTime)
+	if err := insertSamples(samples, int64(currentTime)*1e3); err != nil {
+		flushErrors.Inc()
+		logger.Errorf("cannot write aggregated statsd metrics to the storage: %s", err)
+	}
+}
+
+// InsertHandler processes statsd lines.
+//
+// The received lines are aggregated and written to the storage every -statsd.flushInterval.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+func InsertHandler(r io.Reader) error {
+	return stream.Parse(r, "", func(rows []parser.Row) error {
+		aggr.add(rows, fasttime.UnixTimestamp())
+		return nil
+	})
+}
+
+func insertSamples(samples []sample, timestamp int64) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	ctx := common.GetInsertCtx()
+	defer common.PutInsertCtx(ctx)
+
+	ctx.Reset(len(samples))
+	hasRelabeling := relabel.HasRelabeling()
+	for i := range samples {
+		s := &samples[i]
+		ctx.Labels = ctx.Labels[:0]
+		ctx.AddLabel("", s.metric)
+		for j := range s.tags {
+			tag := &s.tags[j]
+			ctx.AddLabel(tag.Name, tag.Value)
+		}
+		if !ctx.TryPrepareLabels(hasRelabeling) {
+			continue
+		}
+		if err := ctx.WriteDataPoint(nil, ctx.Labels, timestamp, s.value); err != nil {
+			return err
+		}
+	}
+	rowsInserted.Add(len(samples))
+	rowsPerInsert.Update(float64(len(samples)))
+	return ctx.FlushBufs()
+}
+
+func parseQuantiles(s string) ([]float64, error) {
+	var quantiles []float64
+	for _, phiStr := range strings.Split(s, ",") {
+		phiStr = strings.TrimSpace(phiStr)
+		if phiStr == "" {
+			continue
+		}
+		phi, err := strconv.ParseFloat(phiStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse quantile %q: %w", phiStr, err)
+		}
+		if phi < 0 || phi > 1 {
+			return nil, fmt.Errorf("quantile must be in the range [0..1]; got %v", phi)
+		}
+		quantiles = append(quantiles, phi)
+	}
+	return quantiles, nil
+}
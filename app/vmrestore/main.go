@@ -30,6 +30,8 @@ var (
 	concurrency             = flag.Int("concurrency", 10, "The number of concurrent workers. Higher concurrency may reduce restore duration")
 	maxBytesPerSecond       = flagutil.NewBytes("maxBytesPerSecond", 0, "The maximum download speed. There is no limit if it is set to 0")
 	skipBackupCompleteCheck = flag.Bool("skipBackupCompleteCheck", false, "Whether to skip checking for 'backup complete' file in -src. This may be useful for restoring from old backups, which were created without 'backup complete' file")
+	dryRun                  = flag.Bool("dryRun", false, "Whether to only log the files, which would be deleted from -storageDataPath, and the parts, which would be downloaded from -src, without making any changes. "+
+		"See https://docs.victoriametrics.com/victoriametrics/vmrestore/#dry-run")
 )
 
 func main() {
@@ -58,11 +60,17 @@ func main() {
 	if err != nil {
 		logger.Fatalf("%s", err)
 	}
+	encryptionKeys, err := actions.LoadEncryptionKeys()
+	if err != nil {
+		logger.Fatalf("cannot load encryption keys: %s", err)
+	}
 	a := &actions.Restore{
 		Concurrency:             *concurrency,
 		Src:                     srcFS,
 		Dst:                     dstFS,
 		SkipBackupCompleteCheck: *skipBackupCompleteCheck,
+		DryRun:                  *dryRun,
+		EncryptionKeys:          encryptionKeys,
 	}
 	pushmetrics.Init()
 	if err := a.Run(ctx); err != nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsencrypt"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fslocal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsnil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
@@ -42,6 +43,11 @@ type Backup struct {
 	// Origin is optional origin for speeding up full backup if Dst points
 	// to empty dir.
 	Origin common.OriginFS
+
+	// EncryptionKey is optional key for encrypting backup parts before uploading them to Dst.
+	//
+	// Dst must be either empty or contain the backup encrypted with the same key.
+	EncryptionKey *fsencrypt.Key
 }
 
 // BackupMetadata contains metadata about the backup.
@@ -49,6 +55,10 @@ type Backup struct {
 type BackupMetadata struct {
 	CreatedAt   string `json:"created_at"`
 	CompletedAt string `json:"completed_at"`
+
+	// EncryptionKeyID is the ID of the key used for encrypting backup parts.
+	// It is empty for unencrypted backups.
+	EncryptionKeyID string `json:"encryption_key_id,omitempty"`
 }
 
 // Run runs b with the provided settings.
@@ -61,6 +71,42 @@ func (b *Backup) Run() error {
 	if origin != nil && origin.String() == dst.String() {
 		origin = nil
 	}
+	if _, ok := origin.(*fsnil.FS); ok {
+		origin = nil
+	}
+
+	var keyID string
+	if b.EncryptionKey != nil {
+		keyID = b.EncryptionKey.ID
+	}
+	dstKeyID, err := readEncryptionKeyID(dst)
+	if err != nil {
+		return err
+	}
+	if dstKeyID != "" && dstKeyID != keyID {
+		return fmt.Errorf("cannot update the backup at %s encrypted with the key %q using the key %q; use an empty -dst or the same key", dst, dstKeyID, keyID)
+	}
+	if origin != nil {
+		originKeyID, err := getOriginEncryptionKeyID(origin)
+		if err != nil {
+			return err
+		}
+		if originKeyID != keyID {
+			logger.Warnf("ignoring origin %s, since its encryption key %q doesn't match the key %q for %s", origin, originKeyID, keyID, dst)
+			origin = nil
+		}
+	}
+	if b.EncryptionKey != nil {
+		dst = &fsencrypt.FS{
+			Inner: dst,
+			Key:   b.EncryptionKey,
+		}
+		if origin != nil {
+			origin = &fsencrypt.OriginFS{
+				Inner: origin,
+			}
+		}
+	}
 	if origin == nil {
 		origin = &fsnil.FS{}
 	}
@@ -71,7 +117,7 @@ func (b *Backup) Run() error {
 	if err := runBackup(src, dst, origin, concurrency); err != nil {
 		return err
 	}
-	if err := storeMetadata(src, dst); err != nil {
+	if err := storeMetadata(src, dst, keyID); err != nil {
 		return fmt.Errorf("cannot store backup metadata: %w", err)
 	}
 	if err := dst.CreateFile(backupnames.BackupCompleteFilename, nil); err != nil {
@@ -81,7 +127,7 @@ func (b *Backup) Run() error {
 	return nil
 }
 
-func storeMetadata(src *fslocal.FS, dst common.RemoteFS) error {
+func storeMetadata(src *fslocal.FS, dst common.RemoteFS, encryptionKeyID string) error {
 	snapshotName := filepath.Base(src.Dir)
 	snapshotTime, err := snapshotutil.Time(snapshotName)
 	if err != nil {
@@ -91,6 +137,8 @@ func storeMetadata(src *fslocal.FS, dst common.RemoteFS) error {
 	d := BackupMetadata{
 		CreatedAt:   snapshotTime.Format(time.RFC3339),
 		CompletedAt: time.Now().Format(time.RFC3339),
+
+		EncryptionKeyID: encryptionKeyID,
 	}
 
 	metadata, err := json.Marshal(d)
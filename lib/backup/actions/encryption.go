@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsencrypt"
+)
+
+var (
+	encryptionKeyFile = flag.String("encryptionKeyFile", "", "Optional path to file with keys for client-side encryption of backup parts. "+
+		"The path can point either to local file or to http url. See https://docs.victoriametrics.com/victoriametrics/vmbackup/#encryption")
+	encryptionKeyID = flag.String("encryptionKeyID", "", "ID of the key from -encryptionKeyFile for encrypting new backups. The first key from -encryptionKeyFile is used if not set. "+
+		"Backups are decrypted with the key, which ID is stored in backup metadata. See https://docs.victoriametrics.com/victoriametrics/vmbackup/#encryption")
+)
+
+// LoadEncryptionKeys returns keys from -encryptionKeyFile.
+//
+// nil is returned if -encryptionKeyFile isn't set.
+func LoadEncryptionKeys() ([]*fsencrypt.Key, error) {
+	if *encryptionKeyFile == "" {
+		return nil, nil
+	}
+	return fsencrypt.LoadKeys(*encryptionKeyFile)
+}
+
+// GetEncryptionKey returns the key for encrypting new backups according to -encryptionKeyFile and -encryptionKeyID.
+//
+// nil is returned if -encryptionKeyFile isn't set.
+func GetEncryptionKey() (*fsencrypt.Key, error) {
+	keys, err := LoadEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		if *encryptionKeyID != "" {
+			return nil, fmt.Errorf("-encryptionKeyFile must be set when -encryptionKeyID is set")
+		}
+		return nil, nil
+	}
+	if *encryptionKeyID == "" {
+		return keys[0], nil
+	}
+	k := fsencrypt.GetKey(keys, *encryptionKeyID)
+	if k == nil {
+		return nil, fmt.Errorf("cannot find key with -encryptionKeyID=%q at -encryptionKeyFile=%q", *encryptionKeyID, *encryptionKeyFile)
+	}
+	return k, nil
+}
+
+// readEncryptionKeyID returns the ID of the key used for encrypting the backup at fs.
+//
+// Empty string is returned if the backup at fs isn't encrypted or if it has no metadata.
+func readEncryptionKeyID(fs common.RemoteFS) (string, error) {
+	ok, err := fs.HasFile(backupnames.BackupMetadataFilename)
+	if err != nil {
+		return "", fmt.Errorf("cannot check for backup metadata at %s: %w", fs, err)
+	}
+	if !ok {
+		return "", nil
+	}
+	data, err := fs.ReadFile(backupnames.BackupMetadataFilename)
+	if err != nil {
+		return "", fmt.Errorf("cannot read backup metadata from %s: %w", fs, err)
+	}
+	var md BackupMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return "", fmt.Errorf("cannot parse backup metadata from %s: %w", fs, err)
+	}
+	return md.EncryptionKeyID, nil
+}
+
+// newDecryptingFS returns fs, which decrypts backup parts with the key from keys mentioned in backup metadata at fs.
+//
+// fs is returned as is if the backup isn't encrypted.
+func newDecryptingFS(fs common.RemoteFS, keys []*fsencrypt.Key) (common.RemoteFS, error) {
+	keyID, err := readEncryptionKeyID(fs)
+	if err != nil {
+		return nil, err
+	}
+	if keyID == "" {
+		return fs, nil
+	}
+	k := fsencrypt.GetKey(keys, keyID)
+	if k == nil {
+		return nil, fmt.Errorf("backup at %s is encrypted with the key %q, which is missing at -encryptionKeyFile=%q", fs, keyID, *encryptionKeyFile)
+	}
+	return &fsencrypt.FS{
+		Inner: fs,
+		Key:   k,
+	}, nil
+}
+
+// getOriginEncryptionKeyID returns the ID of the key used for encrypting the backup at origin.
+//
+// Origin is assumed to be unencrypted if it doesn't provide access to backup metadata.
+func getOriginEncryptionKeyID(origin common.OriginFS) (string, error) {
+	fs, ok := origin.(common.RemoteFS)
+	if !ok {
+		return "", nil
+	}
+	return readEncryptionKeyID(fs)
+}
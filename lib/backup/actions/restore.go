@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsencrypt"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fslocal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
@@ -39,19 +41,31 @@ type Restore struct {
 	//
 	// This may be needed for restoring from old backups with missing `backup complete` file.
 	SkipBackupCompleteCheck bool
+
+	// DryRun may be set in order to only log the changes, which would be made at Dst during the restore.
+	//
+	// Dst isn't modified in this case.
+	DryRun bool
+
+	// EncryptionKeys contains keys for decrypting encrypted backups.
+	//
+	// The key is selected by the ID stored in backup metadata at Src.
+	EncryptionKeys []*fsencrypt.Key
 }
 
 // Run runs r with the provided settings.
 func (r *Restore) Run(ctx context.Context) error {
 	startTime := time.Now()
 
-	// Make sure VictoriaMetrics doesn't run during the restore process.
-	fs.MustMkdirIfNotExist(r.Dst.Dir)
-	flockF := fs.MustCreateFlockFile(r.Dst.Dir)
-	defer fs.MustClose(flockF)
+	if !r.DryRun {
+		// Make sure VictoriaMetrics doesn't run during the restore process.
+		fs.MustMkdirIfNotExist(r.Dst.Dir)
+		flockF := fs.MustCreateFlockFile(r.Dst.Dir)
+		defer fs.MustClose(flockF)
 
-	if err := createRestoreLock(r.Dst.Dir); err != nil {
-		return err
+		if err := createRestoreLock(r.Dst.Dir); err != nil {
+			return err
+		}
 	}
 	concurrency := r.Concurrency
 	src := r.Src
@@ -68,6 +82,11 @@ func (r *Restore) Run(ctx context.Context) error {
 		}
 	}
 
+	src, err := newDecryptingFS(src, r.EncryptionKeys)
+	if err != nil {
+		return err
+	}
+
 	logger.Infof("starting restore from %s to %s", src, dst)
 
 	logger.Infof("obtaining list of parts at %s", src)
@@ -113,19 +132,13 @@ func (r *Restore) Run(ctx context.Context) error {
 	}
 
 	partsToDelete := common.PartsDifference(dstParts, srcParts)
+	if r.DryRun {
+		logDryRun(src, dst, srcParts, dstParts, partsToDelete, backupSize)
+		return nil
+	}
 	deleteSize := uint64(0)
 	if len(partsToDelete) > 0 {
-		// Remove only files with the missing part at offset 0.
-		// Assume other files are partially downloaded during the previous Restore.Run call,
-		// so only the last part in them may be incomplete.
-		// The last part for partially downloaded files will be re-downloaded later.
-		// This addresses https://github.com/VictoriaMetrics/VictoriaMetrics/issues/487 .
-		pathsToDelete := make(map[string]bool)
-		for _, p := range partsToDelete {
-			if p.Offset == 0 {
-				pathsToDelete[p.Path] = true
-			}
-		}
+		pathsToDelete := getPathsToDelete(partsToDelete)
 		logger.Infof("deleting %d files from %s", len(pathsToDelete), dst)
 		for path := range pathsToDelete {
 			logger.Infof("deleting %s from %s", path, dst)
@@ -196,6 +209,55 @@ func (r *Restore) Run(ctx context.Context) error {
 	return nil
 }
 
+// getPathsToDelete returns paths for files, which must be deleted at dst before downloading partsToDelete.
+func getPathsToDelete(partsToDelete []common.Part) map[string]bool {
+	// Remove only files with the missing part at offset 0.
+	// Assume other files are partially downloaded during the previous Restore.Run call,
+	// so only the last part in them may be incomplete.
+	// The last part for partially downloaded files will be re-downloaded later.
+	// This addresses https://github.com/VictoriaMetrics/VictoriaMetrics/issues/487 .
+	pathsToDelete := make(map[string]bool)
+	for _, p := range partsToDelete {
+		if p.Offset == 0 {
+			pathsToDelete[p.Path] = true
+		}
+	}
+	return pathsToDelete
+}
+
+// logDryRun logs the changes, which would be made at dst during the restore from src.
+func logDryRun(src common.RemoteFS, dst *fslocal.FS, srcParts, dstParts, partsToDelete []common.Part, backupSize uint64) {
+	pathsToDelete := getPathsToDelete(partsToDelete)
+	paths := make([]string, 0, len(pathsToDelete))
+	for path := range pathsToDelete {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		logger.Infof("dry run: would delete %s from %s", path, dst)
+	}
+
+	// Files from pathsToDelete would be deleted, so all their parts would be downloaded.
+	dstPartsLeft := dstParts[:0:0]
+	deleteSize := uint64(0)
+	for _, p := range dstParts {
+		if pathsToDelete[p.Path] {
+			deleteSize += p.Size
+			continue
+		}
+		dstPartsLeft = append(dstPartsLeft, p)
+	}
+	partsToCopy := common.PartsDifference(srcParts, dstPartsLeft)
+	common.SortParts(partsToCopy)
+	for _, p := range partsToCopy {
+		logger.Infof("dry run: would download %s from %s to %s", &p, src, dst)
+	}
+	downloadSize := getPartsSize(partsToCopy)
+
+	logger.Infof("dry run: restore of %d bytes from %s to %s would delete %d files with %d bytes and download %d parts with %d bytes",
+		backupSize, src, dst, len(paths), deleteSize, len(partsToCopy), downloadSize)
+}
+
 type statWriter struct {
 	w            io.Writer
 	bytesWritten *atomic.Uint64
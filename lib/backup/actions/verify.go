@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsencrypt"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fslocal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+// Verify verifies the backup at Dst against the local data at Src.
+//
+// It checks that Dst contains all the parts from Src and only them,
+// and that the contents of every part at Dst matches the contents of the corresponding part at Src.
+// Neither Src nor Dst are modified during the verification.
+type Verify struct {
+	// Concurrency is the number of concurrent workers during the verification.
+	Concurrency int
+
+	// Src is the local data the backup has been made from.
+	Src *fslocal.FS
+
+	// Dst is the backup to verify.
+	Dst common.RemoteFS
+
+	// EncryptionKeys contains keys for decrypting encrypted backups.
+	//
+	// The key is selected by the ID stored in backup metadata at Dst.
+	EncryptionKeys []*fsencrypt.Key
+}
+
+// Run runs v with the provided settings.
+func (v *Verify) Run(ctx context.Context) error {
+	startTime := time.Now()
+	src := v.Src
+	dst := v.Dst
+
+	ok, err := dst.HasFile(backupnames.BackupCompleteFilename)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("cannot find %s file in %s; this means incomplete backup", backupnames.BackupCompleteFilename, dst)
+	}
+
+	dst, err = newDecryptingFS(dst, v.EncryptionKeys)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("starting verification of %s against %s", dst, src)
+
+	srcParts, err := src.ListParts()
+	if err != nil {
+		return fmt.Errorf("cannot list src parts: %w", err)
+	}
+	logger.Infof("obtained %d parts from src %s", len(srcParts), src)
+
+	dstParts, err := dst.ListParts()
+	if err != nil {
+		return fmt.Errorf("cannot list dst parts: %w", err)
+	}
+	logger.Infof("obtained %d parts from dst %s", len(dstParts), dst)
+
+	// Do not use common.PartsDifference here, since it treats parts for mutable files such as parts.json as always different.
+	dstPartsByKey := make(map[string]common.Part, len(dstParts))
+	for _, p := range dstParts {
+		dstPartsByKey[getVerifyPartKey(p)] = p
+	}
+	var inconsistencies atomic.Uint64
+	perPath := make(map[string][]common.Part)
+	for _, p := range srcParts {
+		k := getVerifyPartKey(p)
+		pDst, ok := dstPartsByKey[k]
+		if !ok {
+			logger.Errorf("missing %s at %s", &p, dst)
+			inconsistencies.Add(1)
+			continue
+		}
+		delete(dstPartsByKey, k)
+		if pDst.Size != pDst.ActualSize {
+			logger.Errorf("invalid size for %s at %s; got %d; want %d", &pDst, dst, pDst.ActualSize, pDst.Size)
+			inconsistencies.Add(1)
+			continue
+		}
+		perPath[p.Path] = append(perPath[p.Path], p)
+	}
+	for _, p := range dstPartsByKey {
+		logger.Errorf("unexpected %s at %s; it is missing at %s", &p, dst, src)
+		inconsistencies.Add(1)
+	}
+
+	verifySize := uint64(0)
+	for _, parts := range perPath {
+		verifySize += getPartsSize(parts)
+	}
+	logger.Infof("verifying checksums for %d bytes at %s", verifySize, dst)
+	var bytesVerified atomic.Uint64
+	err = runParallelPerPath(ctx, v.Concurrency, perPath, func(parts []common.Part) error {
+		for _, p := range parts {
+			srcHash, err := getLocalPartHash(src, p)
+			if err != nil {
+				return err
+			}
+			dstHash, err := getRemotePartHash(dst, p)
+			if err != nil {
+				return err
+			}
+			if srcHash != dstHash {
+				logger.Errorf("checksum mismatch for %s; %s has %016X; %s has %016X", &p, src, srcHash, dst, dstHash)
+				inconsistencies.Add(1)
+			}
+			bytesVerified.Add(p.Size)
+		}
+		return nil
+	}, func(elapsed time.Duration) {
+		n := bytesVerified.Load()
+		prc := 100 * float64(n) / float64(verifySize)
+		logger.Infof("verified %d out of %d bytes (%.2f%%) at %s in %s", n, verifySize, prc, dst, elapsed)
+	})
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("verification of %s has been interrupted: %w", dst, err)
+	}
+
+	if n := inconsistencies.Load(); n > 0 {
+		return fmt.Errorf("found %d inconsistencies between %s and %s; see the logs above for details", n, dst, src)
+	}
+	logger.Infof("verification of %s against %s is complete; verified %d parts with %d bytes in %.3f seconds",
+		dst, src, len(srcParts), verifySize, time.Since(startTime).Seconds())
+	return nil
+}
+
+func getVerifyPartKey(p common.Part) string {
+	return fmt.Sprintf("%s%016X%016X%016X", p.Path, p.FileSize, p.Offset, p.Size)
+}
+
+func getLocalPartHash(src *fslocal.FS, p common.Part) (uint64, error) {
+	rc, err := src.NewReadCloser(p)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create reader for %s from %s: %w", &p, src, err)
+	}
+	h := xxhash.New()
+	_, err = io.Copy(h, rc)
+	if errClose := rc.Close(); errClose != nil && err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot read %s from %s: %w", &p, src, err)
+	}
+	return h.Sum64(), nil
+}
+
+func getRemotePartHash(dst common.RemoteFS, p common.Part) (uint64, error) {
+	h := xxhash.New()
+	if err := dst.DownloadPart(p, h); err != nil {
+		return 0, fmt.Errorf("cannot download %s from %s: %w", &p, dst, err)
+	}
+	return h.Sum64(), nil
+}
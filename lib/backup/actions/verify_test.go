@@ -0,0 +1,187 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsencrypt"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fslocal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsnil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsremote"
+)
+
+func TestVerify(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	dstDir := filepath.Join(t.TempDir(), "dst")
+
+	writeFile := func(path, data string) {
+		t.Helper()
+
+		path = filepath.Join(srcDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("cannot create dir: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("cannot write %q: %s", path, err)
+		}
+	}
+	writeFile("data/small/part1/values.bin", "foobar")
+	writeFile("data/small/part1/timestamps.bin", "bazqux")
+	writeFile("data/small/parts.json", `["part1"]`)
+	writeFile("data/big/empty", "")
+
+	src := &fslocal.FS{
+		Dir: srcDir,
+	}
+	if err := src.Init(); err != nil {
+		t.Fatalf("cannot init src: %s", err)
+	}
+	defer src.MustStop()
+	dst := &fsremote.FS{
+		Dir: dstDir,
+	}
+
+	if err := runBackup(src, dst, &fsnil.FS{}, 2); err != nil {
+		t.Fatalf("cannot make backup: %s", err)
+	}
+
+	f := func(resultExpected bool) {
+		t.Helper()
+
+		v := &Verify{
+			Concurrency: 2,
+			Src:         src,
+			Dst:         dst,
+		}
+		err := v.Run(context.Background())
+		if resultExpected && err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !resultExpected && err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// missing `backup complete` file
+	f(false)
+
+	if err := dst.CreateFile(backupnames.BackupCompleteFilename, nil); err != nil {
+		t.Fatalf("cannot create `backup complete` file: %s", err)
+	}
+	f(true)
+
+	// corrupted part at dst
+	parts, err := dst.ListParts()
+	if err != nil {
+		t.Fatalf("cannot list dst parts: %s", err)
+	}
+	var remotePath string
+	for _, p := range parts {
+		if p.Path == "data/small/part1/values.bin" {
+			remotePath = p.RemotePath(dstDir)
+		}
+	}
+	if remotePath == "" {
+		t.Fatalf("cannot find values.bin at dst")
+	}
+	if err := os.WriteFile(remotePath, []byte("barfoo"), 0644); err != nil {
+		t.Fatalf("cannot corrupt %q: %s", remotePath, err)
+	}
+	f(false)
+
+	// missing part at dst
+	if err := os.Remove(remotePath); err != nil {
+		t.Fatalf("cannot remove %q: %s", remotePath, err)
+	}
+	f(false)
+
+	// extra part at dst
+	if err := runBackup(src, dst, &fsnil.FS{}, 2); err != nil {
+		t.Fatalf("cannot make backup: %s", err)
+	}
+	f(true)
+	writeFile("data/small/part2/values.bin", "new")
+	if err := runBackup(src, dst, &fsnil.FS{}, 2); err != nil {
+		t.Fatalf("cannot make backup: %s", err)
+	}
+	if err := os.RemoveAll(filepath.Join(srcDir, "data/small/part2")); err != nil {
+		t.Fatalf("cannot remove part2: %s", err)
+	}
+	f(false)
+}
+
+func TestVerifyEncrypted(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	dstDir := filepath.Join(t.TempDir(), "dst")
+
+	path := filepath.Join(srcDir, "data/small/part1/values.bin")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("cannot create dir: %s", err)
+	}
+	if err := os.WriteFile(path, []byte("foobar"), 0644); err != nil {
+		t.Fatalf("cannot write %q: %s", path, err)
+	}
+
+	src := &fslocal.FS{
+		Dir: srcDir,
+	}
+	if err := src.Init(); err != nil {
+		t.Fatalf("cannot init src: %s", err)
+	}
+	defer src.MustStop()
+	dst := &fsremote.FS{
+		Dir: dstDir,
+	}
+
+	keys, err := fsencrypt.ParseKeys([]byte(`
+- id: key1
+  key: MDEyMzQ1Njc4OWFiY2RlZg==
+- id: key2
+  key: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+`))
+	if err != nil {
+		t.Fatalf("cannot parse keys: %s", err)
+	}
+	dstEncrypted := &fsencrypt.FS{
+		Inner: dst,
+		Key:   keys[1],
+	}
+	if err := runBackup(src, dstEncrypted, &fsnil.FS{}, 2); err != nil {
+		t.Fatalf("cannot make backup: %s", err)
+	}
+	if err := dst.CreateFile(backupnames.BackupMetadataFilename, []byte(`{"encryption_key_id":"key2"}`)); err != nil {
+		t.Fatalf("cannot create backup metadata: %s", err)
+	}
+	if err := dst.CreateFile(backupnames.BackupCompleteFilename, nil); err != nil {
+		t.Fatalf("cannot create `backup complete` file: %s", err)
+	}
+
+	f := func(keys []*fsencrypt.Key, resultExpected bool) {
+		t.Helper()
+
+		v := &Verify{
+			Concurrency:    2,
+			Src:            src,
+			Dst:            dst,
+			EncryptionKeys: keys,
+		}
+		err := v.Run(context.Background())
+		if resultExpected && err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !resultExpected && err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	f(keys, true)
+
+	// missing encryption keys
+	f(nil, false)
+
+	// missing key with the id from backup metadata
+	f(keys[:1], false)
+}
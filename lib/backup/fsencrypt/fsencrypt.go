@@ -0,0 +1,167 @@
+package fsencrypt
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+)
+
+// FS is a RemoteFS, which encrypts parts with Key before uploading them to Inner
+// and decrypts parts with Key after downloading them from Inner.
+//
+// Parts are stored at Inner under the size of encrypted data, while FS exposes them under the original size.
+// Files created via CreateFile aren't encrypted.
+type FS struct {
+	// Inner is the RemoteFS where encrypted parts are stored.
+	Inner common.RemoteFS
+
+	// Key is the key for encrypting and decrypting parts.
+	Key *Key
+}
+
+// OriginFS is an OriginFS with the encrypted parts.
+//
+// It must be used as the origin for FS with the same Key.
+type OriginFS struct {
+	// Inner is the OriginFS where encrypted parts are stored.
+	Inner common.OriginFS
+}
+
+// MustStop stops fs.
+func (fs *FS) MustStop() {
+	fs.Inner.MustStop()
+}
+
+// String returns human-readable description for fs.
+func (fs *FS) String() string {
+	return fmt.Sprintf("%s (encrypted with key %q)", fs.Inner, fs.Key.ID)
+}
+
+// ListParts returns all the parts for fs.
+func (fs *FS) ListParts() ([]common.Part, error) {
+	parts, err := fs.Inner.ListParts()
+	if err != nil {
+		return nil, err
+	}
+	return decryptedParts(parts), nil
+}
+
+// DeletePart deletes the given part p from fs.
+func (fs *FS) DeletePart(p common.Part) error {
+	if p.ActualSize == unencryptedPartActualSize {
+		return fs.Inner.DeletePart(p)
+	}
+	return fs.Inner.DeletePart(encryptedPart(p))
+}
+
+// RemoveEmptyDirs recursively removes empty dirs in fs.
+func (fs *FS) RemoveEmptyDirs() error {
+	return fs.Inner.RemoveEmptyDirs()
+}
+
+// CopyPart copies p from srcFS to fs.
+//
+// srcFS must contain parts encrypted with fs.Key.
+func (fs *FS) CopyPart(srcFS common.OriginFS, p common.Part) error {
+	switch t := srcFS.(type) {
+	case *FS:
+		srcFS = t.Inner
+	case *OriginFS:
+		srcFS = t.Inner
+	}
+	return fs.Inner.CopyPart(srcFS, encryptedPart(p))
+}
+
+// DownloadPart downloads part p from fs to w.
+func (fs *FS) DownloadPart(p common.Part, w io.Writer) error {
+	dw := newDecryptWriter(fs.Key, p, w)
+	if err := fs.Inner.DownloadPart(encryptedPart(p), dw); err != nil {
+		return err
+	}
+	return dw.finish()
+}
+
+// UploadPart uploads part p from r to fs.
+func (fs *FS) UploadPart(p common.Part, r io.Reader) error {
+	er := newEncryptReader(fs.Key, p, r)
+	return fs.Inner.UploadPart(encryptedPart(p), er)
+}
+
+// DeleteFile deletes filePath from fs.
+func (fs *FS) DeleteFile(filePath string) error {
+	return fs.Inner.DeleteFile(filePath)
+}
+
+// CreateFile creates filePath at fs and puts data into it.
+func (fs *FS) CreateFile(filePath string, data []byte) error {
+	return fs.Inner.CreateFile(filePath, data)
+}
+
+// HasFile returns true if filePath exists at fs.
+func (fs *FS) HasFile(filePath string) (bool, error) {
+	return fs.Inner.HasFile(filePath)
+}
+
+// ReadFile returns the content of filePath at fs.
+func (fs *FS) ReadFile(filePath string) ([]byte, error) {
+	return fs.Inner.ReadFile(filePath)
+}
+
+// MustStop stops fs.
+func (fs *OriginFS) MustStop() {
+	fs.Inner.MustStop()
+}
+
+// String returns human-readable description for fs.
+func (fs *OriginFS) String() string {
+	return fmt.Sprintf("%s (encrypted)", fs.Inner)
+}
+
+// ListParts returns all the parts for fs.
+func (fs *OriginFS) ListParts() ([]common.Part, error) {
+	parts, err := fs.Inner.ListParts()
+	if err != nil {
+		return nil, err
+	}
+	return decryptedParts(parts), nil
+}
+
+// ActualSize values for parts listed at the underlying fs, which cannot be decrypted.
+//
+// Such parts never match the original parts, so they are deleted and uploaded again during the backup.
+const (
+	// unencryptedPartActualSize is set for parts, which weren't encrypted.
+	// Such parts are stored at the underlying fs under their original size.
+	unencryptedPartActualSize = math.MaxUint64
+
+	// brokenPartActualSize is set for encrypted parts with unexpected size.
+	brokenPartActualSize = math.MaxUint64 - 1
+)
+
+// encryptedPart returns the part, which is stored at the underlying fs for the original part p.
+func encryptedPart(p common.Part) common.Part {
+	p.Size = encryptedSize(p.Size)
+	p.ActualSize = p.Size
+	return p
+}
+
+// decryptedParts converts parts stored at the underlying fs to the original parts.
+func decryptedParts(parts []common.Part) []common.Part {
+	for i := range parts {
+		p := &parts[i]
+		size, ok := decryptedSize(p.Size)
+		if !ok {
+			p.ActualSize = unencryptedPartActualSize
+			continue
+		}
+		p.Size = size
+		actualSize, ok := decryptedSize(p.ActualSize)
+		if !ok {
+			actualSize = brokenPartActualSize
+		}
+		p.ActualSize = actualSize
+	}
+	return parts
+}
@@ -0,0 +1,240 @@
+package fsencrypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsremote"
+)
+
+func TestEncryptedSize(t *testing.T) {
+	f := func(size uint64) {
+		t.Helper()
+		n := encryptedSize(size)
+		if n <= size {
+			t.Fatalf("encrypted size %d must exceed the original size %d", n, size)
+		}
+		origSize, ok := decryptedSize(n)
+		if !ok {
+			t.Fatalf("cannot obtain the original size from encrypted size %d", n)
+		}
+		if origSize != size {
+			t.Fatalf("unexpected original size; got %d; want %d", origSize, size)
+		}
+	}
+	f(0)
+	f(1)
+	f(chunkSize - 1)
+	f(chunkSize)
+	f(chunkSize + 1)
+	f(10*chunkSize + 123)
+	f(common.MaxPartSize)
+
+	// Sizes, which cannot belong to encrypted parts
+	for _, n := range []uint64{0, 1, chunkOverhead - 1, chunkSize + chunkOverhead + 1} {
+		if _, ok := decryptedSize(n); ok {
+			t.Fatalf("expecting invalid encrypted size %d", n)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	f := func(data string, resultExpected bool) {
+		t.Helper()
+		_, err := ParseKeys([]byte(data))
+		if resultExpected && err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !resultExpected && err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// Valid keys
+	f(`
+- id: key1
+  key: MDEyMzQ1Njc4OWFiY2RlZg==
+- id: key2
+  key: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+`, true)
+
+	// Missing keys
+	f(``, false)
+
+	// Missing id
+	f(`
+- key: MDEyMzQ1Njc4OWFiY2RlZg==
+`, false)
+
+	// Duplicate id
+	f(`
+- id: key1
+  key: MDEyMzQ1Njc4OWFiY2RlZg==
+- id: key1
+  key: MDEyMzQ1Njc4OWFiY2RlZg==
+`, false)
+
+	// Invalid base64
+	f(`
+- id: key1
+  key: foo!
+`, false)
+
+	// Invalid key length
+	f(`
+- id: key1
+  key: Zm9vYmFy
+`, false)
+
+	// Unknown field
+	f(`
+- id: key1
+  key: MDEyMzQ1Njc4OWFiY2RlZg==
+  foo: bar
+`, false)
+}
+
+func TestFSUploadDownload(t *testing.T) {
+	dir := t.TempDir()
+	key := newTestKey(t, "key1")
+	fs := &FS{
+		Inner: &fsremote.FS{
+			Dir: dir,
+		},
+		Key: key,
+	}
+
+	f := func(size int) {
+		t.Helper()
+
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatalf("cannot generate data: %s", err)
+		}
+		p := common.Part{
+			Path:       "data/part",
+			FileSize:   uint64(size),
+			Size:       uint64(size),
+			ActualSize: uint64(size),
+		}
+		if err := fs.UploadPart(p, bytes.NewReader(data)); err != nil {
+			t.Fatalf("cannot upload part: %s", err)
+		}
+
+		parts, err := fs.ListParts()
+		if err != nil {
+			t.Fatalf("cannot list parts: %s", err)
+		}
+		if len(parts) != 1 {
+			t.Fatalf("unexpected number of parts; got %d; want 1", len(parts))
+		}
+		if parts[0].Size != p.Size || parts[0].ActualSize != p.Size {
+			t.Fatalf("unexpected part sizes; got %d and %d; want %d", parts[0].Size, parts[0].ActualSize, p.Size)
+		}
+
+		// Verify the data is stored encrypted
+		pEncrypted := encryptedPart(parts[0])
+		storedData, err := os.ReadFile(pEncrypted.RemotePath(dir))
+		if err != nil {
+			t.Fatalf("cannot read stored part: %s", err)
+		}
+		if len(storedData) != int(encryptedSize(p.Size)) {
+			t.Fatalf("unexpected stored part size; got %d; want %d", len(storedData), encryptedSize(p.Size))
+		}
+		if size > 0 && bytes.Contains(storedData, data) {
+			t.Fatalf("the part data is stored unencrypted")
+		}
+
+		var bb bytes.Buffer
+		if err := fs.DownloadPart(parts[0], &bb); err != nil {
+			t.Fatalf("cannot download part: %s", err)
+		}
+		if !bytes.Equal(bb.Bytes(), data) {
+			t.Fatalf("unexpected data downloaded; got %d bytes; want %d bytes", bb.Len(), len(data))
+		}
+
+		// The part cannot be decrypted with another key
+		fsOther := &FS{
+			Inner: fs.Inner,
+			Key:   newTestKey(t, "key1"),
+		}
+		if err := fsOther.DownloadPart(parts[0], &bb); err == nil {
+			t.Fatalf("expecting non-nil error when decrypting with another key")
+		}
+
+		if err := fs.DeletePart(parts[0]); err != nil {
+			t.Fatalf("cannot delete part: %s", err)
+		}
+		parts, err = fs.ListParts()
+		if err != nil {
+			t.Fatalf("cannot list parts: %s", err)
+		}
+		if len(parts) != 0 {
+			t.Fatalf("unexpected number of parts after the deletion; got %d; want 0", len(parts))
+		}
+	}
+
+	f(0)
+	f(1)
+	f(chunkSize)
+	f(3*chunkSize + 123)
+}
+
+func TestFSListPartsUnencrypted(t *testing.T) {
+	dir := t.TempDir()
+	inner := &fsremote.FS{
+		Dir: dir,
+	}
+	p := common.Part{
+		Path:     "data/part",
+		FileSize: 3,
+		Size:     3,
+	}
+	if err := inner.UploadPart(p, bytes.NewReader([]byte("foo"))); err != nil {
+		t.Fatalf("cannot upload part: %s", err)
+	}
+
+	fs := &FS{
+		Inner: inner,
+		Key:   newTestKey(t, "key1"),
+	}
+	parts, err := fs.ListParts()
+	if err != nil {
+		t.Fatalf("cannot list parts: %s", err)
+	}
+	if len(parts) != 1 {
+		t.Fatalf("unexpected number of parts; got %d; want 1", len(parts))
+	}
+	if parts[0].ActualSize == parts[0].Size {
+		t.Fatalf("unencrypted part must be treated as broken")
+	}
+
+	// Unencrypted part must be deleted from the underlying fs
+	if err := fs.DeletePart(parts[0]); err != nil {
+		t.Fatalf("cannot delete part: %s", err)
+	}
+	parts, err = inner.ListParts()
+	if err != nil {
+		t.Fatalf("cannot list parts: %s", err)
+	}
+	if len(parts) != 0 {
+		t.Fatalf("unencrypted part wasn't deleted")
+	}
+}
+
+func newTestKey(t *testing.T, id string) *Key {
+	t.Helper()
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	k, err := NewKey(id, b)
+	if err != nil {
+		t.Fatalf("cannot create key: %s", err)
+	}
+	return k
+}
@@ -0,0 +1,111 @@
+package fsencrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+)
+
+// Key is a key for encrypting and decrypting backup parts.
+type Key struct {
+	// ID is the key identifier, which is stored in backup metadata.
+	ID string
+
+	aead cipher.AEAD
+}
+
+// keyConfig is a single entry in the file with encryption keys.
+type keyConfig struct {
+	// ID is the key identifier.
+	ID string `yaml:"id"`
+
+	// Key is base64-encoded AES key with 16, 24 or 32 bytes length.
+	Key string `yaml:"key"`
+}
+
+// NewKey returns new Key with the given id for the given AES key.
+//
+// The key must have 16, 24 or 32 bytes length in order to select AES-128, AES-192 or AES-256.
+func NewKey(id string, key []byte) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id cannot be empty")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize AES cipher for key %q: %w", id, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize AES-GCM for key %q: %w", id, err)
+	}
+	return &Key{
+		ID:   id,
+		aead: aead,
+	}, nil
+}
+
+// LoadKeys loads encryption keys from the given path.
+//
+// The path can point either to local file or to http url.
+func LoadKeys(path string) ([]*Key, error) {
+	data, err := fscore.ReadFileOrHTTP(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read encryption keys: %w", err)
+	}
+	data, err = envtemplate.ReplaceBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot expand environment vars at %q: %w", path, err)
+	}
+	keys, err := ParseKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse encryption keys from %q: %w", path, err)
+	}
+	return keys, nil
+}
+
+// ParseKeys parses encryption keys from data.
+func ParseKeys(data []byte) ([]*Key, error) {
+	var kcs []keyConfig
+	if err := yaml.UnmarshalStrict(data, &kcs); err != nil {
+		return nil, err
+	}
+	if len(kcs) == 0 {
+		return nil, fmt.Errorf("missing encryption keys")
+	}
+	keys := make([]*Key, 0, len(kcs))
+	ids := make(map[string]struct{}, len(kcs))
+	for i, kc := range kcs {
+		if _, ok := ids[kc.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", kc.ID)
+		}
+		ids[kc.ID] = struct{}{}
+		b, err := base64.StdEncoding.DecodeString(kc.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode key #%d from base64: %w", i+1, err)
+		}
+		k, err := NewKey(kc.ID, b)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize key #%d: %w", i+1, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// GetKey returns the key with the given id from keys.
+//
+// nil is returned if keys do not contain such a key.
+func GetKey(keys []*Key, id string) *Key {
+	for _, k := range keys {
+		if k.ID == id {
+			return k
+		}
+	}
+	return nil
+}
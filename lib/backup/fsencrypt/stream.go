@@ -0,0 +1,199 @@
+package fsencrypt
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/slicesutil"
+)
+
+// Encrypted part consists of chunks with up to chunkSize bytes of the original data.
+//
+// Every chunk is stored as nonce + ciphertext + tag, so it has chunkOverhead bytes
+// on top of the original data. Chunks are authenticated with the part location, chunk index
+// and the flag for the last chunk, so they cannot be reordered, truncated or moved between parts.
+const (
+	chunkSize     = 64 * 1024
+	nonceSize     = 12
+	tagSize       = 16
+	chunkOverhead = nonceSize + tagSize
+)
+
+// chunksCount returns the number of chunks for the part with the given size.
+func chunksCount(size uint64) uint64 {
+	if size == 0 {
+		// Empty part is stored as a single empty chunk.
+		return 1
+	}
+	return (size + chunkSize - 1) / chunkSize
+}
+
+// encryptedSize returns the size of encrypted part for the original part with the given size.
+func encryptedSize(size uint64) uint64 {
+	return size + chunksCount(size)*chunkOverhead
+}
+
+// decryptedSize returns the size of the original part for the encrypted part with the given size.
+//
+// false is returned if size cannot belong to encrypted part.
+func decryptedSize(size uint64) (uint64, bool) {
+	n := (size + chunkSize + chunkOverhead - 1) / (chunkSize + chunkOverhead)
+	if n == 0 || size < n*chunkOverhead {
+		return 0, false
+	}
+	origSize := size - n*chunkOverhead
+	if encryptedSize(origSize) != size {
+		return 0, false
+	}
+	return origSize, true
+}
+
+// chunkAdditionalData returns additional data for authenticating the chunk with the given index at p.
+func chunkAdditionalData(dst []byte, p *common.Part, chunkIdx uint64, isLast bool) []byte {
+	dst = append(dst, p.Path...)
+	dst = binary.BigEndian.AppendUint64(dst, p.FileSize)
+	dst = binary.BigEndian.AppendUint64(dst, p.Offset)
+	dst = binary.BigEndian.AppendUint64(dst, chunkIdx)
+	if isLast {
+		dst = append(dst, 1)
+	} else {
+		dst = append(dst, 0)
+	}
+	return dst
+}
+
+// encryptReader encrypts p data read from r.
+type encryptReader struct {
+	key *Key
+	p   common.Part
+	r   io.Reader
+
+	chunksTotal uint64
+	chunkIdx    uint64
+	bytesLeft   uint64
+
+	nonce     [nonceSize]byte
+	plain     []byte
+	aad       []byte
+	buf       []byte
+	bufOffset int
+}
+
+func newEncryptReader(key *Key, p common.Part, r io.Reader) *encryptReader {
+	return &encryptReader{
+		key:         key,
+		p:           p,
+		r:           r,
+		chunksTotal: chunksCount(p.Size),
+		bytesLeft:   p.Size,
+	}
+}
+
+// Read implements io.Reader
+func (er *encryptReader) Read(b []byte) (int, error) {
+	if er.bufOffset >= len(er.buf) {
+		if er.chunkIdx >= er.chunksTotal {
+			return 0, io.EOF
+		}
+		if err := er.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, er.buf[er.bufOffset:])
+	er.bufOffset += n
+	return n, nil
+}
+
+func (er *encryptReader) nextChunk() error {
+	n := min(er.bytesLeft, chunkSize)
+	er.plain = slicesutil.SetLength(er.plain, int(n))
+	if _, err := io.ReadFull(er.r, er.plain); err != nil {
+		return fmt.Errorf("cannot read %d bytes for chunk #%d of %s: %w", n, er.chunkIdx, &er.p, err)
+	}
+	er.bytesLeft -= n
+	isLast := er.chunkIdx == er.chunksTotal-1
+
+	if _, err := rand.Read(er.nonce[:]); err != nil {
+		return fmt.Errorf("cannot generate nonce: %w", err)
+	}
+	er.aad = chunkAdditionalData(er.aad[:0], &er.p, er.chunkIdx, isLast)
+	er.buf = append(er.buf[:0], er.nonce[:]...)
+	er.buf = er.key.aead.Seal(er.buf, er.nonce[:], er.plain, er.aad)
+	er.bufOffset = 0
+	er.chunkIdx++
+	return nil
+}
+
+// decryptWriter decrypts encrypted p data and writes it to w.
+type decryptWriter struct {
+	key *Key
+	p   common.Part
+	w   io.Writer
+
+	chunksTotal uint64
+	chunkIdx    uint64
+	bytesLeft   uint64
+
+	buf   []byte
+	plain []byte
+	aad   []byte
+}
+
+func newDecryptWriter(key *Key, p common.Part, w io.Writer) *decryptWriter {
+	return &decryptWriter{
+		key:         key,
+		p:           p,
+		w:           w,
+		chunksTotal: chunksCount(p.Size),
+		bytesLeft:   p.Size,
+	}
+}
+
+// Write implements io.Writer
+func (dw *decryptWriter) Write(b []byte) (int, error) {
+	bLen := len(b)
+	for len(b) > 0 {
+		if dw.chunkIdx >= dw.chunksTotal {
+			return 0, fmt.Errorf("unexpected data after the last chunk of %s", &dw.p)
+		}
+		chunkLen := int(min(dw.bytesLeft, chunkSize)) + chunkOverhead
+		n := min(chunkLen-len(dw.buf), len(b))
+		dw.buf = append(dw.buf, b[:n]...)
+		b = b[n:]
+		if len(dw.buf) < chunkLen {
+			continue
+		}
+		if err := dw.flushChunk(); err != nil {
+			return 0, err
+		}
+	}
+	return bLen, nil
+}
+
+func (dw *decryptWriter) flushChunk() error {
+	isLast := dw.chunkIdx == dw.chunksTotal-1
+	dw.aad = chunkAdditionalData(dw.aad[:0], &dw.p, dw.chunkIdx, isLast)
+	plain, err := dw.key.aead.Open(dw.plain[:0], dw.buf[:nonceSize], dw.buf[nonceSize:], dw.aad)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt chunk #%d of %s with key %q: %w", dw.chunkIdx, &dw.p, dw.key.ID, err)
+	}
+	dw.plain = plain
+	if _, err := dw.w.Write(plain); err != nil {
+		return err
+	}
+	dw.bytesLeft -= uint64(len(plain))
+	dw.buf = dw.buf[:0]
+	dw.chunkIdx++
+	return nil
+}
+
+// finish verifies that all the chunks were decrypted.
+func (dw *decryptWriter) finish() error {
+	if dw.chunkIdx != dw.chunksTotal {
+		return fmt.Errorf("unexpected end of %s; decrypted %d out of %d chunks", &dw.p, dw.chunkIdx, dw.chunksTotal)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package logclient provides Go client for VictoriaLogs.
+//
+// The client supports ingesting logs via JSON stream API, executing LogsQL queries and live tailing.
+// See https://docs.victoriametrics.com/victorialogs/
+package logclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config contains params for the Client.
+type Config struct {
+	// Addr is VictoriaLogs address such as http://localhost:9428
+	Addr string
+
+	// Transport is an optional transport for the requests to VictoriaLogs.
+	//
+	// http.DefaultTransport is used if Transport isn't set.
+	Transport http.RoundTripper
+
+	// User is an optional username for basic auth
+	User string
+
+	// Password is an optional password for basic auth
+	Password string
+
+	// BearerToken is an optional bearer token for the Authorization header
+	BearerToken string
+
+	// AccountID is an optional tenant AccountID.
+	// See https://docs.victoriametrics.com/victorialogs/#multitenancy
+	AccountID uint32
+
+	// ProjectID is an optional tenant ProjectID.
+	// See https://docs.victoriametrics.com/victorialogs/#multitenancy
+	ProjectID uint32
+}
+
+// Client is a client for VictoriaLogs.
+//
+// It is safe calling Client methods from concurrently running goroutines.
+type Client struct {
+	addr        string
+	c           *http.Client
+	user        string
+	password    string
+	bearerToken string
+	accountID   string
+	projectID   string
+}
+
+// NewClient returns new Client for the given cfg.
+func NewClient(cfg *Config) (*Client, error) {
+	addr := strings.TrimRight(cfg.Addr, "/")
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse addr %q: %w", cfg.Addr, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme in addr %q; supported schemes: http, https", cfg.Addr)
+	}
+	if cfg.BearerToken != "" && cfg.User != "" {
+		return nil, fmt.Errorf("BearerToken and User cannot be set simultaneously")
+	}
+	c := &http.Client{
+		Transport: cfg.Transport,
+	}
+	return &Client{
+		addr:        addr,
+		c:           c,
+		user:        cfg.User,
+		password:    cfg.Password,
+		bearerToken: cfg.BearerToken,
+		accountID:   fmt.Sprintf("%d", cfg.AccountID),
+		projectID:   fmt.Sprintf("%d", cfg.ProjectID),
+	}, nil
+}
+
+// Ping checks whether VictoriaLogs is healthy.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodGet, "/health", nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// do performs the request to the given path at c.
+//
+// The caller must close the returned response body.
+func (c *Client) do(ctx context.Context, method, path string, args url.Values, body io.Reader) (*http.Response, error) {
+	u := c.addr + path
+	if len(args) > 0 {
+		u += "?" + args.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request to %q: %w", u, err)
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	req.Header.Set("AccountID", c.accountID)
+	req.Header.Set("ProjectID", c.projectID)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/stream+json")
+	}
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot perform request to %q: %w", c.addr+path, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		_ = resp.Body.Close()
+		return nil, &StatusError{
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Body:       string(respBody),
+		}
+	}
+	return resp, nil
+}
+
+// StatusError is returned when VictoriaLogs responds with unexpected status code.
+type StatusError struct {
+	// Path is the request path
+	Path string
+	// StatusCode is the response status code
+	StatusCode int
+	// Body is the response body prefix
+	Body string
+}
+
+// Error implements error interface.
+func (se *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d for %q; response body: %q", se.StatusCode, se.Path, se.Body)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,303 @@
+package logclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientInsert(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s:%s\n%s", r.Method, r.URL.RequestURI(), r.Header.Get("AccountID"), r.Header.Get("ProjectID"), body))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		Addr:      srv.URL,
+		AccountID: 12,
+		ProjectID: 34,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+	b := c.NewBatcher(2, &InsertOptions{
+		StreamFields: []string{"host", "app"},
+	})
+	for i := 0; i < 3; i++ {
+		r := Row{
+			Fields: []Field{
+				{Name: "_time", Value: fmt.Sprintf("2024-01-02T03:04:0%dZ", i)},
+				{Name: "_msg", Value: fmt.Sprintf("foo \"%d\"", i)},
+				{Name: "host", Value: "host-1"},
+			},
+		}
+		if err := b.Add(context.Background(), r); err != nil {
+			t.Fatalf("cannot add row: %s", err)
+		}
+	}
+	if len(requests) != 1 {
+		t.Fatalf("unexpected number of requests before Flush; got %d; want 1", len(requests))
+	}
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush rows: %s", err)
+	}
+
+	requestsExpected := []string{
+		`POST /insert/jsonline?_stream_fields=host%2Capp 12:34
+{"_time":"2024-01-02T03:04:00Z","_msg":"foo \"0\"","host":"host-1"}
+{"_time":"2024-01-02T03:04:01Z","_msg":"foo \"1\"","host":"host-1"}
+`,
+		`POST /insert/jsonline?_stream_fields=host%2Capp 12:34
+{"_time":"2024-01-02T03:04:02Z","_msg":"foo \"2\"","host":"host-1"}
+`,
+	}
+	if !reflect.DeepEqual(requests, requestsExpected) {
+		t.Fatalf("unexpected requests\ngot\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(requestsExpected, "\n"))
+	}
+}
+
+func TestBatcherRetry(t *testing.T) {
+	var isUnavailable atomic.Bool
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isUnavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		Addr: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+	newRow := func(i int) Row {
+		return Row{
+			Fields: []Field{{Name: "_msg", Value: fmt.Sprintf("%d", i)}},
+		}
+	}
+
+	// rows are kept on errors and are re-sent after VictoriaLogs becomes available
+	isUnavailable.Store(true)
+	b := c.NewBatcher(2, nil)
+	if err := b.Add(context.Background(), newRow(0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := b.Add(context.Background(), newRow(1)); err == nil {
+		t.Fatalf("expecting non-nil error when VictoriaLogs is unavailable")
+	}
+	if err := b.Add(context.Background(), newRow(2)); err == nil {
+		t.Fatalf("expecting non-nil error when VictoriaLogs is unavailable")
+	}
+	isUnavailable.Store(false)
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush rows: %s", err)
+	}
+	requestsExpected := []string{"{\"_msg\":\"0\"}\n{\"_msg\":\"1\"}\n{\"_msg\":\"2\"}\n"}
+	if !reflect.DeepEqual(requests, requestsExpected) {
+		t.Fatalf("unexpected requests\ngot\n%q\nwant\n%q", requests, requestsExpected)
+	}
+
+	// the oldest rows are dropped when the number of pending rows exceeds the limit
+	requests = nil
+	isUnavailable.Store(true)
+	b = c.NewBatcher(1, nil)
+	for i := 0; i < maxPendingBatches+2; i++ {
+		err := b.Add(context.Background(), newRow(i))
+		if err == nil {
+			t.Fatalf("expecting non-nil error when VictoriaLogs is unavailable")
+		}
+		if i >= maxPendingBatches && !strings.Contains(err.Error(), "dropped 1 oldest rows") {
+			t.Fatalf("expecting error about dropped rows; got %s", err)
+		}
+	}
+	isUnavailable.Store(false)
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("cannot flush rows: %s", err)
+	}
+	var bb strings.Builder
+	for i := 2; i < maxPendingBatches+2; i++ {
+		fmt.Fprintf(&bb, "{\"_msg\":\"%d\"}\n", i)
+	}
+	requestsExpected = []string{bb.String()}
+	if !reflect.DeepEqual(requests, requestsExpected) {
+		t.Fatalf("unexpected requests\ngot\n%q\nwant\n%q", requests, requestsExpected)
+	}
+}
+
+func TestClientQuery(t *testing.T) {
+	var requestURI string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.URL.RequestURI()
+		fmt.Fprint(w, `{"_time":"2024-01-02T03:04:05Z","_stream":"{app=\"foo\"}","_msg":"bar baz"}
+{"_msg":"qux","hits":"42"}
+`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		Addr: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+	rows, err := c.Query(context.Background(), "error", &QueryOptions{
+		Start: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("cannot execute query: %s", err)
+	}
+	defer rows.Close()
+
+	var result []Row
+	for rows.Next() {
+		r := rows.Row()
+		result = append(result, Row{
+			Fields: append([]Field{}, r.Fields...),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	requestURIExpected := "/select/logsql/query?limit=10&query=error&start=2024-01-02T00%3A00%3A00Z"
+	if requestURI != requestURIExpected {
+		t.Fatalf("unexpected request URI\ngot\n%s\nwant\n%s", requestURI, requestURIExpected)
+	}
+	resultExpected := []Row{
+		{
+			Fields: []Field{
+				{Name: "_time", Value: "2024-01-02T03:04:05Z"},
+				{Name: "_stream", Value: `{app="foo"}`},
+				{Name: "_msg", Value: "bar baz"},
+			},
+		},
+		{
+			Fields: []Field{
+				{Name: "_msg", Value: "qux"},
+				{Name: "hits", Value: "42"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, resultExpected) {
+		t.Fatalf("unexpected rows\ngot\n%v\nwant\n%v", result, resultExpected)
+	}
+
+	ts, err := result[0].Time()
+	if err != nil {
+		t.Fatalf("cannot parse _time: %s", err)
+	}
+	if !ts.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected _time: %s", ts)
+	}
+	if msg := result[0].Msg(); msg != "bar baz" {
+		t.Fatalf("unexpected _msg: %q", msg)
+	}
+}
+
+func TestClientTail(t *testing.T) {
+	var requestURI string
+	doneCh := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.URL.RequestURI()
+		flusher := w.(http.Flusher)
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, `{"_time":"2024-01-02T03:04:0%dZ","_msg":"line %d"}`+"\n", i, i)
+			flusher.Flush()
+		}
+		// Keep the connection open until the client stops tailing.
+		select {
+		case <-r.Context().Done():
+		case <-doneCh:
+		}
+	}))
+	defer srv.Close()
+	defer close(doneCh)
+
+	c, err := NewClient(&Config{
+		Addr: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rows, err := c.Tail(ctx, `{app="foo"}`)
+	if err != nil {
+		t.Fatalf("cannot start tailing: %s", err)
+	}
+	defer rows.Close()
+
+	// rows must be returned as soon as they are streamed by the server
+	for i := 0; i < 3; i++ {
+		if !rows.Next() {
+			t.Fatalf("cannot read row #%d: %v", i, rows.Err())
+		}
+		r := rows.Row()
+		if msg := r.Msg(); msg != fmt.Sprintf("line %d", i) {
+			t.Fatalf("unexpected _msg for row #%d: %q", i, msg)
+		}
+	}
+	requestURIExpected := "/select/logsql/tail?query=%7Bapp%3D%22foo%22%7D"
+	if requestURI != requestURIExpected {
+		t.Fatalf("unexpected request URI\ngot\n%s\nwant\n%s", requestURI, requestURIExpected)
+	}
+
+	// canceling ctx stops tailing
+	cancel()
+	if rows.Next() {
+		t.Fatalf("unexpected row after canceling tailing: %v", rows.Row())
+	}
+}
+
+func TestClientQueryFailure(t *testing.T) {
+	f := func(statusCode int, response string) {
+		t.Helper()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(statusCode)
+			fmt.Fprint(w, response)
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(&Config{
+			Addr: srv.URL,
+		})
+		if err != nil {
+			t.Fatalf("cannot create client: %s", err)
+		}
+		rows, err := c.Query(context.Background(), "*", nil)
+		if err != nil {
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+		}
+		if rows.Err() == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// bad request
+	f(http.StatusBadRequest, "cannot parse query")
+
+	// invalid response
+	f(http.StatusOK, `{"_msg":"foo"}
+[1,2]
+`)
+	f(http.StatusOK, `{"_msg":"foo"`)
+}
@@ -0,0 +1,203 @@
+package logclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field is a single log field.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Row is a single log entry.
+//
+// See https://docs.victoriametrics.com/victorialogs/keyconcepts/#data-model
+type Row struct {
+	Fields []Field
+}
+
+// Get returns the value for the field with the given name.
+//
+// Empty string is returned if r has no the given field.
+func (r *Row) Get(name string) string {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Msg returns the value of `_msg` field.
+func (r *Row) Msg() string {
+	return r.Get("_msg")
+}
+
+// Stream returns the value of `_stream` field.
+func (r *Row) Stream() string {
+	return r.Get("_stream")
+}
+
+// Time returns the parsed value of `_time` field.
+func (r *Row) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, r.Get("_time"))
+}
+
+// InsertOptions contains optional params for Client.Insert.
+//
+// See https://docs.victoriametrics.com/victorialogs/data-ingestion/#http-parameters
+type InsertOptions struct {
+	// StreamFields is the list of fields, which must be used as log stream fields.
+	StreamFields []string
+
+	// IgnoreFields is the list of fields, which must be ignored during ingestion.
+	IgnoreFields []string
+}
+
+// Insert sends rows to VictoriaLogs via JSON stream API.
+//
+// Rows may contain `_time` field in RFC3339 format. The ingestion time is used for rows without `_time` field.
+//
+// See https://docs.victoriametrics.com/victorialogs/data-ingestion/#json-stream-api
+func (c *Client) Insert(ctx context.Context, rows []Row, opts *InsertOptions) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	args := url.Values{}
+	if opts != nil {
+		if len(opts.StreamFields) > 0 {
+			args.Set("_stream_fields", strings.Join(opts.StreamFields, ","))
+		}
+		if len(opts.IgnoreFields) > 0 {
+			args.Set("ignore_fields", strings.Join(opts.IgnoreFields, ","))
+		}
+	}
+	var bb bytes.Buffer
+	var line []byte
+	for i := range rows {
+		line = rows[i].marshal(line[:0])
+		line = append(line, '\n')
+		bb.Write(line)
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/insert/jsonline", args, &bb)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// marshal appends JSON representation of r to dst and returns the result.
+func (r *Row) marshal(dst []byte) []byte {
+	dst = append(dst, '{')
+	for i, f := range r.Fields {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendJSONString(dst, f.Name)
+		dst = append(dst, ':')
+		dst = appendJSONString(dst, f.Value)
+	}
+	dst = append(dst, '}')
+	return dst
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
+}
+
+// Batcher accumulates rows and sends them to VictoriaLogs in batches.
+//
+// Rows, which couldn't be sent because of errors, are kept in Batcher and are re-sent on the next Add or Flush call.
+// Batcher keeps up to maxPendingBatches*maxRows rows. The oldest rows are dropped when this limit is exceeded.
+// The number of dropped rows is reported in the error returned from Add or Flush.
+//
+// It is safe calling Batcher methods from concurrently running goroutines.
+type Batcher struct {
+	c       *Client
+	opts    *InsertOptions
+	maxRows int
+
+	mu   sync.Mutex
+	rows []Row
+}
+
+// maxPendingBatches is the maximum number of batches Batcher keeps while VictoriaLogs is unavailable.
+const maxPendingBatches = 10
+
+// NewBatcher returns new Batcher, which sends rows to c in batches of up to maxRows rows.
+//
+// opts are passed to Client.Insert for every batch.
+// Call Flush for sending the remaining rows when the Batcher is no longer needed.
+func (c *Client) NewBatcher(maxRows int, opts *InsertOptions) *Batcher {
+	if maxRows < 1 {
+		maxRows = 1000
+	}
+	return &Batcher{
+		c:       c,
+		opts:    opts,
+		maxRows: maxRows,
+	}
+}
+
+// Add adds r to b.
+//
+// The accumulated rows are sent to VictoriaLogs when their number reaches the batch size.
+func (b *Batcher) Add(ctx context.Context, r Row) error {
+	b.mu.Lock()
+	b.rows = append(b.rows, r)
+	if len(b.rows) < b.maxRows {
+		b.mu.Unlock()
+		return nil
+	}
+	rows := b.rows
+	b.rows = nil
+	b.mu.Unlock()
+
+	return b.send(ctx, rows)
+}
+
+// Flush sends all the accumulated rows to VictoriaLogs.
+func (b *Batcher) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	rows := b.rows
+	b.rows = nil
+	b.mu.Unlock()
+
+	return b.send(ctx, rows)
+}
+
+// send sends rows to VictoriaLogs.
+//
+// rows are returned back to b on error, so they are re-sent on the next call.
+func (b *Batcher) send(ctx context.Context, rows []Row) error {
+	err := b.c.Insert(ctx, rows, b.opts)
+	if err == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	// Put rows before the rows added during Insert call in order to preserve the order of rows.
+	b.rows = append(rows, b.rows...)
+	dropped := len(b.rows) - maxPendingBatches*b.maxRows
+	if dropped > 0 {
+		b.rows = append(b.rows[:0], b.rows[dropped:]...)
+	}
+	pending := len(b.rows)
+	b.mu.Unlock()
+
+	if dropped > 0 {
+		return fmt.Errorf("cannot send %d rows: %w; dropped %d oldest rows, since the number of pending rows exceeds %d", len(rows), err, dropped, maxPendingBatches*b.maxRows)
+	}
+	return fmt.Errorf("cannot send %d rows: %w; %d pending rows will be re-sent on the next call", len(rows), err, pending)
+}
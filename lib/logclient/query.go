@@ -0,0 +1,178 @@
+package logclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// QueryOptions contains optional params for Client.Query.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#querying-logs
+type QueryOptions struct {
+	// Start is an optional lower bound for `_time` field of the selected logs
+	Start time.Time
+
+	// End is an optional upper bound for `_time` field of the selected logs
+	End time.Time
+
+	// Limit is an optional limit on the number of returned logs
+	Limit int
+}
+
+// Query executes LogsQL query q and returns the iterator over the selected logs.
+//
+// The caller must call Rows.Close when the returned Rows are no longer needed.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/
+func (c *Client) Query(ctx context.Context, q string, opts *QueryOptions) (*Rows, error) {
+	args := url.Values{}
+	args.Set("query", q)
+	if opts != nil {
+		if !opts.Start.IsZero() {
+			args.Set("start", formatTime(opts.Start))
+		}
+		if !opts.End.IsZero() {
+			args.Set("end", formatTime(opts.End))
+		}
+		if opts.Limit > 0 {
+			args.Set("limit", strconv.Itoa(opts.Limit))
+		}
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/select/logsql/query", args, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(resp.Body), nil
+}
+
+// Tail executes live tailing for LogsQL query q and returns the iterator over the newly ingested logs.
+//
+// The returned Rows are blocked until new logs arrive. Cancel ctx or call Rows.Close in order to stop tailing.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#live-tailing
+func (c *Client) Tail(ctx context.Context, q string) (*Rows, error) {
+	args := url.Values{}
+	args.Set("query", q)
+	resp, err := c.do(ctx, http.MethodPost, "/select/logsql/tail", args, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(resp.Body), nil
+}
+
+// Rows is an iterator over the logs returned by Client.Query and Client.Tail.
+//
+// Typical usage:
+//
+//	rows, err := c.Query(ctx, q, nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		r := rows.Row()
+//		...
+//	}
+//	return rows.Err()
+type Rows struct {
+	rc  io.ReadCloser
+	dec *json.Decoder
+	row Row
+	err error
+}
+
+func newRows(rc io.ReadCloser) *Rows {
+	dec := json.NewDecoder(bufio.NewReader(rc))
+	dec.UseNumber()
+	return &Rows{
+		rc:  rc,
+		dec: dec,
+	}
+}
+
+// Next advances rs to the next row.
+//
+// It returns false when there are no more rows or on error. Check Err in this case.
+func (rs *Rows) Next() bool {
+	if rs.err != nil {
+		return false
+	}
+	row, err := readRow(rs.dec, rs.row.Fields[:0])
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			rs.err = err
+		}
+		return false
+	}
+	rs.row = row
+	return true
+}
+
+// Row returns the current row.
+//
+// The returned row is valid until the next call to Next.
+func (rs *Rows) Row() Row {
+	return rs.row
+}
+
+// Err returns the error occurred during the iteration.
+func (rs *Rows) Err() error {
+	return rs.err
+}
+
+// Close releases resources occupied by rs.
+func (rs *Rows) Close() error {
+	return rs.rc.Close()
+}
+
+// readRow reads JSON object with log fields from dec, while preserving the order of fields.
+func readRow(dec *json.Decoder, fields []Field) (Row, error) {
+	t, err := dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Row{}, err
+		}
+		return Row{}, fmt.Errorf("cannot read log entry: %w", err)
+	}
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return Row{}, fmt.Errorf("unexpected token %v; want JSON object with log fields", t)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return Row{}, fmt.Errorf("cannot read field name: %w", err)
+		}
+		name, ok := t.(string)
+		if !ok {
+			return Row{}, fmt.Errorf("unexpected field name %v", t)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return Row{}, fmt.Errorf("cannot read value for field %q: %w", name, err)
+		}
+		value := string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return Row{}, fmt.Errorf("cannot parse value for field %q: %w", name, err)
+			}
+		}
+		fields = append(fields, Field{
+			Name:  name,
+			Value: value,
+		})
+	}
+	if _, err := dec.Token(); err != nil {
+		return Row{}, fmt.Errorf("cannot read the end of log entry: %w", err)
+	}
+	return Row{
+		Fields: fields,
+	}, nil
+}
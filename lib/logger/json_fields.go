@@ -11,6 +11,12 @@ var loggerJSONFields = flag.String("loggerJSONFields", "", `Allows renaming fiel
 	"Supported fields: ts, level, caller, msg")
 
 func setLoggerJSONFields() {
+	if *loggerFormat == "victorialogs" {
+		// Use field names from VictoriaLogs data model, so logs can be ingested into VictoriaLogs via JSON stream API as is.
+		// See https://docs.victoriametrics.com/victorialogs/keyconcepts/#data-model
+		fieldTs = "_time"
+		fieldMsg = "_msg"
+	}
 	if *loggerJSONFields == "" {
 		return
 	}
@@ -18,8 +18,10 @@ import (
 )
 
 var (
-	loggerLevel    = flag.String("loggerLevel", "INFO", "Minimum level of errors to log. Possible values: INFO, WARN, ERROR, FATAL, PANIC")
-	loggerFormat   = flag.String("loggerFormat", "default", "Format for logs. Possible values: default, json")
+	loggerLevel  = flag.String("loggerLevel", "INFO", "Minimum level of errors to log. Possible values: INFO, WARN, ERROR, FATAL, PANIC")
+	loggerFormat = flag.String("loggerFormat", "default", "Format for logs. Possible values: default, json, victorialogs. "+
+		"The victorialogs format emits JSON logs with _time, level, caller and _msg fields, which are ready for ingestion into VictoriaLogs via JSON stream API. "+
+		"See https://docs.victoriametrics.com/victorialogs/data-ingestion/#json-stream-api")
 	loggerOutput   = flag.String("loggerOutput", "stderr", "Output for the logs. Supported values: stderr, stdout")
 	loggerTimezone = flag.String("loggerTimezone", "UTC", "Timezone to use for timestamps in logs. Timezone must be a valid IANA Time Zone. "+
 		"For example: America/New_York, Europe/Berlin, Etc/GMT+3 or Local")
@@ -94,10 +96,10 @@ func validateLoggerLevel() {
 
 func validateLoggerFormat() {
 	switch *loggerFormat {
-	case "default", "json":
+	case "default", "json", "victorialogs":
 	default:
 		// We cannot use logger.Panicf here, since the logger isn't initialized yet.
-		panic(fmt.Errorf("FATAL: unsupported `-loggerFormat` value: %q; supported values are: default, json", *loggerFormat))
+		panic(fmt.Errorf("FATAL: unsupported `-loggerFormat` value: %q; supported values are: default, json, victorialogs", *loggerFormat))
 	}
 }
 
@@ -238,7 +240,12 @@ func (lw *logWriter) Write(p []byte) (int, error) {
 func logMessage(level, msg string, skipframes int) {
 	timestamp := ""
 	if !*disableTimestamps {
-		timestamp = time.Now().In(timezone).Format("2006-01-02T15:04:05.000Z0700")
+		timestampFormat := "2006-01-02T15:04:05.000Z0700"
+		if *loggerFormat == "victorialogs" {
+			// VictoriaLogs expects _time field in RFC3339 format.
+			timestampFormat = time.RFC3339Nano
+		}
+		timestamp = time.Now().In(timezone).Format(timestampFormat)
 	}
 	levelLowercase := strings.ToLower(level)
 	_, file, line, ok := runtime.Caller(skipframes)
@@ -272,23 +279,8 @@ func logMessage(level, msg string, skipframes int) {
 	}
 	var logMsg string
 	switch *loggerFormat {
-	case "json":
-		if *disableTimestamps {
-			logMsg = fmt.Sprintf(
-				`{%q:%q,%q:%q,%q:%q}`+"\n",
-				fieldLevel, levelLowercase,
-				fieldCaller, location,
-				fieldMsg, msg,
-			)
-		} else {
-			logMsg = fmt.Sprintf(
-				`{%q:%q,%q:%q,%q:%q,%q:%q}`+"\n",
-				fieldTs, timestamp,
-				fieldLevel, levelLowercase,
-				fieldCaller, location,
-				fieldMsg, msg,
-			)
-		}
+	case "json", "victorialogs":
+		logMsg = formatJSONMessage(timestamp, levelLowercase, location, msg)
 	default:
 		if *disableTimestamps {
 			logMsg = fmt.Sprintf("%s\t%s\t%s\n", levelLowercase, location, msg)
@@ -308,8 +300,8 @@ func logMessage(level, msg string, skipframes int) {
 
 	switch level {
 	case "PANIC":
-		if *loggerFormat == "json" {
-			// Do not clutter `json` output with panic stack trace
+		if *loggerFormat != "default" {
+			// Do not clutter `json` and `victorialogs` output with panic stack trace
 			os.Exit(-1)
 		}
 		panic(errors.New(msg))
@@ -318,6 +310,27 @@ func logMessage(level, msg string, skipframes int) {
 	}
 }
 
+// formatJSONMessage returns log line in JSON format with field names set via -loggerJSONFields.
+//
+// The timestamp field is omitted if timestamp is empty.
+func formatJSONMessage(timestamp, level, location, msg string) string {
+	if timestamp == "" {
+		return fmt.Sprintf(
+			`{%q:%q,%q:%q,%q:%q}`+"\n",
+			fieldLevel, level,
+			fieldCaller, location,
+			fieldMsg, msg,
+		)
+	}
+	return fmt.Sprintf(
+		`{%q:%q,%q:%q,%q:%q,%q:%q}`+"\n",
+		fieldTs, timestamp,
+		fieldLevel, level,
+		fieldCaller, location,
+		fieldMsg, msg,
+	)
+}
+
 var mu sync.Mutex
 
 func shouldSkipLog(level string) bool {
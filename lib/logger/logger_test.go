@@ -23,3 +23,36 @@ func TestFormatLogMessage(t *testing.T) {
 	// Format args exceeding the maxArgLen
 	f("foo: %s, %q, %s", []any{"abcde", fmt.Errorf("foo bar baz"), "xx"}, 4, `foo: a..e, "f..z", xx`)
 }
+
+func TestFormatJSONMessage(t *testing.T) {
+	f := func(format, jsonFields, timestamp, level, location, msg, resultExpected string) {
+		t.Helper()
+
+		defer func(formatOrig, fields, ts, lvl, caller, m string) {
+			*loggerFormat = formatOrig
+			*loggerJSONFields = fields
+			fieldTs, fieldLevel, fieldCaller, fieldMsg = ts, lvl, caller, m
+		}(*loggerFormat, *loggerJSONFields, fieldTs, fieldLevel, fieldCaller, fieldMsg)
+
+		*loggerFormat = format
+		*loggerJSONFields = jsonFields
+		setLoggerJSONFields()
+		result := formatJSONMessage(timestamp, level, location, msg)
+		if result != resultExpected {
+			t.Fatalf("unexpected result; got\n%q\nwant\n%q", result, resultExpected)
+		}
+	}
+
+	// json format
+	f("json", "", "2024-01-02T03:04:05.123Z", "info", "lib/foo/bar.go:12", "foo", `{"ts":"2024-01-02T03:04:05.123Z","level":"info","caller":"lib/foo/bar.go:12","msg":"foo"}`+"\n")
+	f("json", "ts:timestamp,msg:message", "2024-01-02T03:04:05.123Z", "info", "lib/foo/bar.go:12", "foo", `{"timestamp":"2024-01-02T03:04:05.123Z","level":"info","caller":"lib/foo/bar.go:12","message":"foo"}`+"\n")
+
+	// victorialogs format
+	f("victorialogs", "", "2024-01-02T03:04:05.123456789Z", "info", "lib/foo/bar.go:12", "foo", `{"_time":"2024-01-02T03:04:05.123456789Z","level":"info","caller":"lib/foo/bar.go:12","_msg":"foo"}`+"\n")
+
+	// victorialogs format with empty timestamp
+	f("victorialogs", "", "", "error", "app/baz.go:34", `cannot open "file"`, `{"level":"error","caller":"app/baz.go:34","_msg":"cannot open \"file\""}`+"\n")
+
+	// victorialogs format with -loggerJSONFields
+	f("victorialogs", "level:severity", "2024-01-02T03:04:05Z", "warn", "app/baz.go:34", "foo", `{"_time":"2024-01-02T03:04:05Z","severity":"warn","caller":"app/baz.go:34","_msg":"foo"}`+"\n")
+}
@@ -334,10 +334,15 @@ type ScrapeConfig struct {
 	StreamParse         bool                       `yaml:"stream_parse,omitempty"`
 	ScrapeAlignInterval *promutil.Duration         `yaml:"scrape_align_interval,omitempty"`
 	ScrapeOffset        *promutil.Duration         `yaml:"scrape_offset,omitempty"`
+	MaxScrapeInterval   *promutil.Duration         `yaml:"max_scrape_interval,omitempty"`
 	SeriesLimit         *int                       `yaml:"series_limit,omitempty"`
 	NoStaleMarkers      *bool                      `yaml:"no_stale_markers,omitempty"`
 	ProxyClientConfig   promauth.ProxyClientConfig `yaml:",inline"`
 
+	DisableAutoMetrics        bool   `yaml:"disable_auto_metrics,omitempty"`
+	AutoMetricsPrefix         string `yaml:"auto_metrics_prefix,omitempty"`
+	NoAutoMetricsStaleMarkers bool   `yaml:"no_auto_metrics_stale_markers,omitempty"`
+
 	// This is set in loadConfig
 	swc *scrapeWorkConfig
 }
@@ -907,6 +912,10 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 		// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1281#issuecomment-840538907
 		scrapeTimeout = scrapeInterval
 	}
+	maxScrapeInterval := sc.MaxScrapeInterval.Duration()
+	if maxScrapeInterval > 0 && maxScrapeInterval < scrapeInterval {
+		return nil, fmt.Errorf("`max_scrape_interval` for `job_name` %q cannot be smaller than `scrape_interval`; got %s vs %s", jobName, maxScrapeInterval, scrapeInterval)
+	}
 	mss := maxScrapeSize.N
 	if sc.MaxScrapeSize != "" {
 		n, err := flagutil.ParseBytes(sc.MaxScrapeSize)
@@ -981,6 +990,7 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 		scrapeIntervalString: scrapeInterval.String(),
 		scrapeTimeout:        scrapeTimeout,
 		scrapeTimeoutString:  scrapeTimeout.String(),
+		maxScrapeInterval:    maxScrapeInterval,
 		maxScrapeSize:        mss,
 		jobName:              jobName,
 		metricsPath:          metricsPath,
@@ -1004,6 +1014,10 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 		scrapeOffset:         sc.ScrapeOffset.Duration(),
 		seriesLimit:          seriesLimit,
 		noStaleMarkers:       noStaleTracking,
+
+		disableAutoMetrics:        sc.DisableAutoMetrics,
+		autoMetricsPrefix:         sc.AutoMetricsPrefix,
+		noAutoMetricsStaleMarkers: sc.NoAutoMetricsStaleMarkers,
 	}
 	return swc, nil
 }
@@ -1013,6 +1027,7 @@ type scrapeWorkConfig struct {
 	scrapeIntervalString string
 	scrapeTimeout        time.Duration
 	scrapeTimeoutString  string
+	maxScrapeInterval    time.Duration
 	maxScrapeSize        int64
 	jobName              string
 	metricsPath          string
@@ -1036,6 +1051,10 @@ type scrapeWorkConfig struct {
 	scrapeOffset         time.Duration
 	seriesLimit          int
 	noStaleMarkers       bool
+
+	disableAutoMetrics        bool
+	autoMetricsPrefix         string
+	noAutoMetricsStaleMarkers bool
 }
 
 func appendScrapeWorkForTargetLabels(dst []*ScrapeWork, swc *scrapeWorkConfig, targetLabels []*promutil.Labels, discoveryType string) []*ScrapeWork {
@@ -1246,6 +1265,12 @@ func (swc *scrapeWorkConfig) getScrapeWork(target string, extraLabels, metaLabel
 		}
 		scrapeTimeout = d
 	}
+	// Disable adaptive scrape interval if the scrape interval for the target
+	// has been increased via __scrape_interval__ label beyond max_scrape_interval.
+	maxScrapeInterval := swc.maxScrapeInterval
+	if maxScrapeInterval <= scrapeInterval {
+		maxScrapeInterval = 0
+	}
 	// Read series_limit option from __series_limit__ label.
 	// See https://docs.victoriametrics.com/victoriametrics/vmagent/#cardinality-limiter
 	seriesLimit := swc.seriesLimit
@@ -1309,6 +1334,7 @@ func (swc *scrapeWorkConfig) getScrapeWork(target string, extraLabels, metaLabel
 		ScrapeURL:            scrapeURL,
 		ScrapeInterval:       scrapeInterval,
 		ScrapeTimeout:        scrapeTimeout,
+		MaxScrapeInterval:    maxScrapeInterval,
 		MaxScrapeSize:        swc.maxScrapeSize,
 		HonorLabels:          swc.honorLabels,
 		HonorTimestamps:      swc.honorTimestamps,
@@ -1332,6 +1358,10 @@ func (swc *scrapeWorkConfig) getScrapeWork(target string, extraLabels, metaLabel
 		NoStaleMarkers:       swc.noStaleMarkers,
 		AuthToken:            at,
 
+		DisableAutoMetrics:        swc.disableAutoMetrics,
+		AutoMetricsPrefix:         swc.autoMetricsPrefix,
+		NoAutoMetricsStaleMarkers: swc.noAutoMetricsStaleMarkers,
+
 		jobNameOriginal: swc.jobName,
 	}
 	return sw, nil
@@ -663,6 +663,16 @@ scrape_configs:
   - targets: ["foo"]
 `, []*ScrapeWork{})
 
+	// Scrape config with max_scrape_interval smaller than scrape_interval must be skipped
+	f(`
+scrape_configs:
+- job_name: x
+  scrape_interval: 10s
+  max_scrape_interval: 5s
+  static_configs:
+  - targets: ["foo"]
+`, []*ScrapeWork{})
+
 	// Scrape config with missing job_name must be skipped
 	f(`
 scrape_configs:
@@ -1240,6 +1250,67 @@ scrape_configs:
 		},
 	})
 
+	f(`
+scrape_configs:
+- job_name: foo
+  disable_auto_metrics: true
+  auto_metrics_prefix: vmagent_
+  no_auto_metrics_stale_markers: true
+  static_configs:
+  - targets: ["foo.bar:1234"]
+`, []*ScrapeWork{
+		{
+			ScrapeURL:                 "http://foo.bar:1234/metrics",
+			ScrapeInterval:            defaultScrapeInterval,
+			ScrapeTimeout:             defaultScrapeTimeout,
+			MaxScrapeSize:             maxScrapeSize.N,
+			DisableAutoMetrics:        true,
+			AutoMetricsPrefix:         "vmagent_",
+			NoAutoMetricsStaleMarkers: true,
+			Labels: promutil.NewLabelsFromMap(map[string]string{
+				"instance": "foo.bar:1234",
+				"job":      "foo",
+			}),
+			jobNameOriginal: "foo",
+		},
+	})
+
+	f(`
+scrape_configs:
+- job_name: foo
+  scrape_interval: 10s
+  max_scrape_interval: 1m
+  static_configs:
+  - targets: ["foo.bar:1234"]
+  - targets: ["foo.bar:5678"]
+    labels:
+      __scrape_interval__: 2m
+`, []*ScrapeWork{
+		{
+			ScrapeURL:         "http://foo.bar:1234/metrics",
+			ScrapeInterval:    10 * time.Second,
+			ScrapeTimeout:     10 * time.Second,
+			MaxScrapeInterval: time.Minute,
+			MaxScrapeSize:     maxScrapeSize.N,
+			Labels: promutil.NewLabelsFromMap(map[string]string{
+				"instance": "foo.bar:1234",
+				"job":      "foo",
+			}),
+			jobNameOriginal: "foo",
+		},
+		{
+			ScrapeURL:      "http://foo.bar:5678/metrics",
+			ScrapeInterval: 2 * time.Minute,
+			ScrapeTimeout:  10 * time.Second,
+			MaxScrapeSize:  maxScrapeSize.N,
+			Labels: promutil.NewLabelsFromMap(map[string]string{
+				"instance": "foo.bar:5678",
+				"job":      "foo",
+			}),
+			jobNameOriginal: "foo",
+		},
+	})
+
 	defaultSeriesLimitPerTarget := *seriesLimitPerTarget
 	*seriesLimitPerTarget = 1e3
 	f(`
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math"
 	"math/bits"
+	"net"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -59,6 +60,13 @@ type ScrapeWork struct {
 	// Timeout for scraping the ScrapeURL.
 	ScrapeTimeout time.Duration
 
+	// The maximum interval the scrape interval can be backed off to
+	// if the target consistently exceeds ScrapeTimeout.
+	//
+	// Adaptive scrape interval is disabled if MaxScrapeInterval is zero.
+	// See https://docs.victoriametrics.com/victoriametrics/vmagent/#adaptive-scrape-interval
+	MaxScrapeInterval time.Duration
+
 	// MaxScrapeSize sets max amount of data, that can be scraped by a job
 	MaxScrapeSize int64
 
@@ -152,6 +160,16 @@ type ScrapeWork struct {
 	// See https://docs.victoriametrics.com/victoriametrics/vmagent/#prometheus-staleness-markers
 	NoStaleMarkers bool
 
+	// Whether to disable generating `up` and `scrape_*` metrics for the given target.
+	// See https://docs.victoriametrics.com/victoriametrics/vmagent/#automatically-generated-metrics
+	DisableAutoMetrics bool
+
+	// Optional prefix to add to the names of automatically generated metrics.
+	AutoMetricsPrefix string
+
+	// Whether to disable sending stale markers for automatically generated metrics when the target disappears.
+	NoAutoMetricsStaleMarkers bool
+
 	// The Tenant Info
 	AuthToken *auth.Token
 
@@ -172,16 +190,18 @@ func (sw *ScrapeWork) key() string {
 	// Do not take into account OriginalLabels, since they can be changed with relabeling.
 	// Do not take into account RelabelConfigs, since it is already applied to Labels.
 	// Take into account JobNameOriginal in order to capture the case when the original job_name is changed via relabeling.
-	key := fmt.Sprintf("JobNameOriginal=%s, ScrapeURL=%s, ScrapeInterval=%s, ScrapeTimeout=%s, HonorLabels=%v, "+
+	key := fmt.Sprintf("JobNameOriginal=%s, ScrapeURL=%s, ScrapeInterval=%s, ScrapeTimeout=%s, MaxScrapeInterval=%s, HonorLabels=%v, "+
 		"HonorTimestamps=%v, DenyRedirects=%v, Labels=%s, ExternalLabels=%s, MaxScrapeSize=%d, "+
 		"ProxyURL=%s, ProxyAuthConfig=%s, AuthConfig=%s, MetricRelabelConfigs=%q, "+
 		"SampleLimit=%d, DisableCompression=%v, DisableKeepAlive=%v, StreamParse=%v, "+
-		"ScrapeAlignInterval=%s, ScrapeOffset=%s, SeriesLimit=%d, LabelLimit=%d, NoStaleMarkers=%v",
-		sw.jobNameOriginal, sw.ScrapeURL, sw.ScrapeInterval, sw.ScrapeTimeout, sw.HonorLabels,
+		"ScrapeAlignInterval=%s, ScrapeOffset=%s, SeriesLimit=%d, LabelLimit=%d, NoStaleMarkers=%v, "+
+		"DisableAutoMetrics=%v, AutoMetricsPrefix=%q, NoAutoMetricsStaleMarkers=%v",
+		sw.jobNameOriginal, sw.ScrapeURL, sw.ScrapeInterval, sw.ScrapeTimeout, sw.MaxScrapeInterval, sw.HonorLabels,
 		sw.HonorTimestamps, sw.DenyRedirects, sw.Labels.String(), sw.ExternalLabels.String(), sw.MaxScrapeSize,
 		sw.ProxyURL.String(), sw.ProxyAuthConfig.String(), sw.AuthConfig.String(), sw.MetricRelabelConfigs.String(),
 		sw.SampleLimit, sw.DisableCompression, sw.DisableKeepAlive, sw.StreamParse,
-		sw.ScrapeAlignInterval, sw.ScrapeOffset, sw.SeriesLimit, sw.LabelLimit, sw.NoStaleMarkers)
+		sw.ScrapeAlignInterval, sw.ScrapeOffset, sw.SeriesLimit, sw.LabelLimit, sw.NoStaleMarkers,
+		sw.DisableAutoMetrics, sw.AutoMetricsPrefix, sw.NoAutoMetricsStaleMarkers)
 	return key
 }
 
@@ -242,6 +262,77 @@ type scrapeWork struct {
 
 	// successRequestsCount is the number of success requests during the last suppressScrapeErrorsDelay
 	successRequestsCount int
+
+	// effectiveScrapeInterval is the current scrape interval for the target.
+	// It may differ from Config.ScrapeInterval if Config.MaxScrapeInterval is set.
+	effectiveScrapeInterval time.Duration
+
+	// timedOutScrapesCount is the number of consecutive scrapes, which exceeded Config.ScrapeTimeout.
+	timedOutScrapesCount int
+
+	// successScrapesCount is the number of consecutive scrapes, which didn't exceed Config.ScrapeTimeout.
+	successScrapesCount int
+}
+
+// adaptiveScrapeIntervalThreshold is the number of consecutive timed out or successful scrapes
+// needed for increasing or decreasing the effective scrape interval when adaptive scrape interval is enabled.
+const adaptiveScrapeIntervalThreshold = 3
+
+// getEffectiveScrapeInterval returns the current scrape interval for sw.
+func (sw *scrapeWork) getEffectiveScrapeInterval() time.Duration {
+	if sw.effectiveScrapeInterval <= 0 {
+		return sw.Config.ScrapeInterval
+	}
+	return sw.effectiveScrapeInterval
+}
+
+// updateEffectiveScrapeInterval adjusts the effective scrape interval for sw according to the scrape error err.
+//
+// The effective scrape interval is doubled up to Config.MaxScrapeInterval after adaptiveScrapeIntervalThreshold
+// consecutive scrapes exceeding Config.ScrapeTimeout, and it is halved down to Config.ScrapeInterval after
+// adaptiveScrapeIntervalThreshold consecutive scrapes without timeouts.
+func (sw *scrapeWork) updateEffectiveScrapeInterval(err error) {
+	maxInterval := sw.Config.MaxScrapeInterval
+	if maxInterval <= 0 {
+		return
+	}
+	minInterval := sw.Config.ScrapeInterval
+	interval := sw.getEffectiveScrapeInterval()
+	if isTimeoutError(err) {
+		sw.successScrapesCount = 0
+		sw.timedOutScrapesCount++
+		if sw.timedOutScrapesCount < adaptiveScrapeIntervalThreshold {
+			return
+		}
+		sw.timedOutScrapesCount = 0
+		interval = min(2*interval, maxInterval)
+	} else {
+		sw.timedOutScrapesCount = 0
+		if interval <= minInterval {
+			return
+		}
+		sw.successScrapesCount++
+		if sw.successScrapesCount < adaptiveScrapeIntervalThreshold {
+			return
+		}
+		sw.successScrapesCount = 0
+		interval = max(interval/2, minInterval)
+	}
+	if interval != sw.getEffectiveScrapeInterval() {
+		scrapeIntervalAdjustments.Inc()
+	}
+	sw.effectiveScrapeInterval = interval
+}
+
+func isTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
 }
 
 // loadLastScrape appends last scrape response to dst and returns the result.
@@ -321,6 +412,12 @@ func (sw *scrapeWork) run(stopCh <-chan struct{}, globalStopCh <-chan struct{})
 	}
 	defer ticker.Stop()
 	for {
+		if interval := sw.getEffectiveScrapeInterval(); interval != scrapeInterval {
+			// The effective scrape interval has been changed by adaptive scrape interval logic.
+			// See https://docs.victoriametrics.com/victoriametrics/vmagent/#adaptive-scrape-interval
+			scrapeInterval = interval
+			ticker.Reset(scrapeInterval)
+		}
 		timestamp += scrapeInterval.Milliseconds()
 		select {
 		case <-stopCh:
@@ -374,6 +471,7 @@ func (sw *scrapeWork) logError(s string) {
 
 func (sw *scrapeWork) scrapeAndLogError(scrapeTimestamp, realTimestamp int64) {
 	err := sw.scrapeInternal(scrapeTimestamp, realTimestamp)
+	sw.updateEffectiveScrapeInterval(err)
 	if *suppressScrapeErrors {
 		return
 	}
@@ -405,6 +503,7 @@ var (
 	scrapesSkippedBySampleLimit = metrics.NewCounter("vm_promscrape_scrapes_skipped_by_sample_limit_total")
 	scrapesSkippedByLabelLimit  = metrics.NewCounter("vm_promscrape_scrapes_skipped_by_label_limit_total")
 	scrapesFailed               = metrics.NewCounter("vm_promscrape_scrapes_failed_total")
+	scrapeIntervalAdjustments   = metrics.NewCounter("vm_promscrape_scrape_interval_adjustments_total")
 	pushDataDuration            = metrics.NewHistogram("vm_promscrape_push_data_duration_seconds")
 )
 
@@ -884,7 +983,7 @@ func (sw *scrapeWork) sendStaleSeries(lastScrape, currScrape string, timestamp i
 			sw.logError(fmt.Errorf("cannot send stale markers: %w", err).Error())
 		}
 	}
-	if addAutoSeries {
+	if addAutoSeries && !sw.Config.DisableAutoMetrics && !sw.Config.NoAutoMetricsStaleMarkers {
 		var wc writeRequestCtx
 		var am autoMetrics
 		wc.addAutoMetrics(sw, &am, timestamp)
@@ -936,6 +1035,21 @@ type autoMetrics struct {
 	seriesLimitSamplesDropped int
 }
 
+// isAutoMetric returns true if s clashes with the name of automatically generated metric for sw.
+func (sw *ScrapeWork) isAutoMetric(s string) bool {
+	if sw.DisableAutoMetrics {
+		return false
+	}
+	if sw.AutoMetricsPrefix != "" {
+		var ok bool
+		s, ok = strings.CutPrefix(s, sw.AutoMetricsPrefix)
+		if !ok {
+			return false
+		}
+	}
+	return isAutoMetric(s)
+}
+
 func isAutoMetric(s string) bool {
 	if s == "up" {
 		return true
@@ -945,6 +1059,7 @@ func isAutoMetric(s string) bool {
 	}
 	switch s {
 	case "scrape_duration_seconds",
+		"scrape_effective_interval_seconds",
 		"scrape_response_size_bytes",
 		"scrape_samples_limit",
 		"scrape_samples_post_metric_relabeling",
@@ -966,6 +1081,9 @@ func isAutoMetric(s string) bool {
 //
 // sw is used as read-only config source.
 func (wc *writeRequestCtx) addAutoMetrics(sw *scrapeWork, am *autoMetrics, timestamp int64) {
+	if sw.Config.DisableAutoMetrics {
+		return
+	}
 	rows := getAutoRows()
 	dst := slicesutil.SetLength(rows.Rows, 11)[:0]
 
@@ -988,9 +1106,25 @@ func (wc *writeRequestCtx) addAutoMetrics(sw *scrapeWork, am *autoMetrics, times
 	if labelLimit := sw.Config.LabelLimit; labelLimit > 0 {
 		dst = appendRow(dst, "scrape_labels_limit", float64(labelLimit), timestamp)
 	}
+	if sw.Config.MaxScrapeInterval > 0 {
+		// Expose the current scrape interval if adaptive scrape interval is enabled for the target.
+		// See https://docs.victoriametrics.com/victoriametrics/vmagent/#adaptive-scrape-interval
+		dst = appendRow(dst, "scrape_effective_interval_seconds", sw.getEffectiveScrapeInterval().Seconds(), timestamp)
+	}
 	dst = appendRow(dst, "scrape_timeout_seconds", sw.Config.ScrapeTimeout.Seconds(), timestamp)
 	dst = appendRow(dst, "up", float64(am.up), timestamp)
 
+	if prefix := sw.Config.AutoMetricsPrefix; prefix != "" {
+		bb := bbPool.Get()
+		for i := range dst {
+			r := &dst[i]
+			bb.B = append(bb.B[:0], prefix...)
+			bb.B = append(bb.B, r.Metric...)
+			r.Metric = bytesutil.InternBytes(bb.B)
+		}
+		bbPool.Put(bb)
+	}
+
 	err := wc.addRows(sw.Config, dst, timestamp, false)
 	if err != nil {
 		sw.logError(fmt.Errorf("cannot add auto metrics: %w", err).Error())
@@ -1067,7 +1201,7 @@ func (wc *writeRequestCtx) addRow(cfg *ScrapeWork, r *parser.Row, timestamp int6
 	//
 	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/3557
 	// and https://github.com/VictoriaMetrics/VictoriaMetrics/issues/3406
-	if needRelabel && !cfg.HonorLabels && len(r.Tags) == 0 && isAutoMetric(metric) {
+	if needRelabel && !cfg.HonorLabels && len(r.Tags) == 0 && cfg.isAutoMetric(metric) {
 		bb := bbPool.Get()
 		bb.B = append(bb.B, "exported_"...)
 		bb.B = append(bb.B, metric...)
@@ -1,6 +1,7 @@
 package promscrape
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -35,12 +36,56 @@ func TestIsAutoMetric(t *testing.T) {
 	f("scrape_series_limit_samples_dropped", true)
 	f("scrape_series_limit", true)
 	f("scrape_series_current", true)
+	f("scrape_effective_interval_seconds", true)
 
 	f("foobar", false)
 	f("exported_up", false)
 	f("upx", false)
 }
 
+func TestScrapeWorkUpdateEffectiveScrapeInterval(t *testing.T) {
+	timeoutErr := fmt.Errorf("cannot perform request: %w", context.DeadlineExceeded)
+	otherErr := fmt.Errorf("unexpected status code returned")
+
+	f := func(maxScrapeInterval time.Duration, errs []error, intervalExpected time.Duration) {
+		t.Helper()
+		sw := &scrapeWork{
+			Config: &ScrapeWork{
+				ScrapeInterval:    10 * time.Second,
+				MaxScrapeInterval: maxScrapeInterval,
+			},
+		}
+		for _, err := range errs {
+			sw.updateEffectiveScrapeInterval(err)
+		}
+		interval := sw.getEffectiveScrapeInterval()
+		if interval != intervalExpected {
+			t.Fatalf("unexpected effective scrape interval; got %s; want %s", interval, intervalExpected)
+		}
+	}
+
+	// adaptive scrape interval is disabled
+	f(0, []error{timeoutErr, timeoutErr, timeoutErr}, 10*time.Second)
+
+	// not enough timed out scrapes
+	f(time.Minute, []error{timeoutErr, timeoutErr}, 10*time.Second)
+	f(time.Minute, []error{timeoutErr, timeoutErr, nil, timeoutErr}, 10*time.Second)
+
+	// non-timeout errors do not increase the interval
+	f(time.Minute, []error{otherErr, otherErr, otherErr}, 10*time.Second)
+
+	// the interval is doubled after consecutive timeouts
+	f(time.Minute, []error{timeoutErr, timeoutErr, timeoutErr}, 20*time.Second)
+	f(time.Minute, []error{timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr}, 40*time.Second)
+
+	// the interval is limited by max_scrape_interval
+	f(30*time.Second, []error{timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr}, 30*time.Second)
+
+	// the interval is halved after consecutive successful scrapes
+	f(time.Minute, []error{timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr, timeoutErr, nil, nil, nil}, 20*time.Second)
+	f(time.Minute, []error{timeoutErr, timeoutErr, timeoutErr, nil, nil, nil, nil, nil, nil}, 10*time.Second)
+}
+
 func TestAppendExtraLabels(t *testing.T) {
 	f := func(sourceLabels, extraLabels string, honorLabels bool, resultExpected string) {
 		t.Helper()
@@ -548,6 +593,39 @@ func testScrapeWorkScrapeInternalSuccess(t *testing.T, streamParse bool) {
 		scrape_series_limit_samples_dropped 1 123
 		scrape_timeout_seconds 42 123
 	`)
+	// Disable auto metrics.
+	f(`
+		foo{bar="baz"} 34.44
+		up 5
+	`, &ScrapeWork{
+		StreamParse:        streamParse,
+		ScrapeTimeout:      time.Second * 42,
+		DisableAutoMetrics: true,
+	}, `
+		foo{bar="baz"} 34.44 123
+		up 5 123
+	`)
+	// Add prefix to auto metrics.
+	f(`
+		foo{bar="baz"} 34.44
+		up 5
+		vmagent_up 6
+	`, &ScrapeWork{
+		StreamParse:       streamParse,
+		ScrapeTimeout:     time.Second * 42,
+		AutoMetricsPrefix: "vmagent_",
+	}, `
+		foo{bar="baz"} 34.44 123
+		up 5 123
+		exported_vmagent_up 6 123
+		vmagent_up 1 123
+		vmagent_scrape_samples_scraped 3 123
+		vmagent_scrape_response_size_bytes 47 123
+		vmagent_scrape_duration_seconds 0 123
+		vmagent_scrape_samples_post_metric_relabeling 3 123
+		vmagent_scrape_series_added 3 123
+		vmagent_scrape_timeout_seconds 42 123
+	`)
 }
 
 // TestScrapeWorkScrapeInternalStreamConcurrency ensures that streaming parsing with concurrency
@@ -914,6 +992,36 @@ func TestSendStaleSeries(t *testing.T) {
 	f(generateScrape(20000), generateScrape(10), 19990)
 }
 
+func TestSendStaleSeriesAutoMetrics(t *testing.T) {
+	f := func(cfg *ScrapeWork, staleMarksExpected int64) {
+		t.Helper()
+		var sw scrapeWork
+		sw.Config = cfg
+		protoparserutil.StartUnmarshalWorkers()
+		defer protoparserutil.StopUnmarshalWorkers()
+
+		var staleMarks atomic.Int64
+		sw.PushData = func(_ *auth.Token, wr *prompb.WriteRequest) {
+			staleMarks.Add(int64(len(wr.Timeseries)))
+		}
+		sw.sendStaleSeries("foo 1\nbar 2\n", "", 0, true)
+		if staleMarks.Load() != staleMarksExpected {
+			t.Fatalf("unexpected number of stale marks; got %d; want %d", staleMarks.Load(), staleMarksExpected)
+		}
+	}
+
+	f(&ScrapeWork{}, 9)
+	f(&ScrapeWork{
+		DisableAutoMetrics: true,
+	}, 2)
+	f(&ScrapeWork{
+		NoAutoMetricsStaleMarkers: true,
+	}, 2)
+	f(&ScrapeWork{
+		NoStaleMarkers: true,
+	}, 0)
+}
+
 func parsePromRow(data string) *prometheus.Row {
 	var rows prometheus.Rows
 	errLogger := func(s string) {
@@ -964,7 +1072,7 @@ func timeseriesToMap(tss []prompb.TimeSeries) (map[string]string, error) {
 		if ts.Labels[0].Name != "__name__" {
 			return nil, fmt.Errorf("unexpected first name for timeseries #%d; got %q; want %q", i, ts.Labels[0].Name, "__name__")
 		}
-		if ts.Labels[0].Value == "scrape_duration_seconds" {
+		if strings.HasSuffix(ts.Labels[0].Value, "scrape_duration_seconds") {
 			// Reset scrape_duration_seconds value to 0, since it is non-deterministic
 			ts.Samples[0].Value = 0
 		}
@@ -0,0 +1,291 @@
+package statsd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/datadogutil"
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Supported statsd metric types.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+const (
+	TypeCounter      = "c"
+	TypeGauge        = "g"
+	TypeTimer        = "ms"
+	TypeHistogram    = "h"
+	TypeDistribution = "d"
+	TypeSet          = "s"
+)
+
+// Rows contains parsed statsd rows.
+type Rows struct {
+	Rows []Row
+
+	tagsPool   []Tag
+	valuesPool []float64
+}
+
+// Reset resets rs.
+func (rs *Rows) Reset() {
+	// Reset items, so they can be GC'ed
+
+	for i := range rs.Rows {
+		rs.Rows[i].reset()
+	}
+	rs.Rows = rs.Rows[:0]
+
+	for i := range rs.tagsPool {
+		rs.tagsPool[i].reset()
+	}
+	rs.tagsPool = rs.tagsPool[:0]
+
+	rs.valuesPool = rs.valuesPool[:0]
+}
+
+// Unmarshal unmarshals statsd lines from s.
+//
+// The following line format is supported:
+//
+//	<metric>[,<tag>=<value>...]:<value>[:<value>...]|<type>[|@<sample_rate>][|#<tag>:<value>,...]
+//
+// Tags can be passed either in InfluxDB style after the metric name or in DogStatsD style after `|#`.
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+// and https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/
+//
+// s shouldn't be modified when rs is in use.
+func (rs *Rows) Unmarshal(s string) {
+	rs.Rows, rs.tagsPool, rs.valuesPool = unmarshalRows(rs.Rows[:0], s, rs.tagsPool[:0], rs.valuesPool[:0])
+}
+
+// Row is a single statsd row.
+type Row struct {
+	Metric string
+	Tags   []Tag
+	Type   string
+
+	// Values contains numeric values for all the types except of TypeSet.
+	Values []float64
+
+	// SetValue contains the value for TypeSet.
+	SetValue string
+
+	// IsDelta is set for TypeGauge if the value starts with `+` or `-`.
+	// In this case the value must be added to the current gauge value.
+	IsDelta bool
+
+	// SampleRate is the sample rate for the row in the range (0..1]
+	SampleRate float64
+}
+
+func (r *Row) reset() {
+	r.Metric = ""
+	r.Tags = nil
+	r.Type = ""
+	r.Values = nil
+	r.SetValue = ""
+	r.IsDelta = false
+	r.SampleRate = 0
+}
+
+func (r *Row) unmarshal(s string, tagsPool []Tag, valuesPool []float64) ([]Tag, []float64, error) {
+	r.reset()
+	n := strings.IndexByte(s, '|')
+	if n < 0 {
+		return tagsPool, valuesPool, fmt.Errorf("cannot find metric type in %q", s)
+	}
+	metricAndValues := s[:n]
+	s = s[n+1:]
+
+	n = strings.IndexByte(s, '|')
+	if n < 0 {
+		r.Type = s
+		s = ""
+	} else {
+		r.Type = s[:n]
+		s = s[n+1:]
+	}
+	switch r.Type {
+	case TypeCounter, TypeGauge, TypeTimer, TypeHistogram, TypeDistribution, TypeSet:
+	default:
+		return tagsPool, valuesPool, fmt.Errorf("unsupported metric type %q; supported types: c, g, ms, h, d, s", r.Type)
+	}
+
+	n = strings.IndexByte(metricAndValues, ':')
+	if n <= 0 {
+		return tagsPool, valuesPool, fmt.Errorf("cannot find metric name in %q", metricAndValues)
+	}
+	metricAndTags := metricAndValues[:n]
+	valuesStr := metricAndValues[n+1:]
+
+	tagsStart := len(tagsPool)
+	n = strings.IndexByte(metricAndTags, ',')
+	if n < 0 {
+		r.Metric = metricAndTags
+	} else {
+		r.Metric = metricAndTags[:n]
+		tagsPool = unmarshalTags(tagsPool, metricAndTags[n+1:], ',', '=')
+	}
+	if len(r.Metric) == 0 {
+		return tagsPool, valuesPool, fmt.Errorf("metric cannot be empty")
+	}
+
+	r.SampleRate = 1
+	for len(s) > 0 {
+		var ext string
+		n = strings.IndexByte(s, '|')
+		if n < 0 {
+			ext = s
+			s = ""
+		} else {
+			ext = s[:n]
+			s = s[n+1:]
+		}
+		if len(ext) == 0 {
+			continue
+		}
+		switch ext[0] {
+		case '@':
+			sampleRate, err := fastfloat.Parse(ext[1:])
+			if err != nil {
+				return tagsPool, valuesPool, fmt.Errorf("cannot parse sample rate from %q: %w", ext, err)
+			}
+			if sampleRate <= 0 || sampleRate > 1 {
+				return tagsPool, valuesPool, fmt.Errorf("sample rate must be in the range (0..1]; got %v", sampleRate)
+			}
+			r.SampleRate = sampleRate
+		case '#':
+			tagsPool = unmarshalTags(tagsPool, ext[1:], ',', ':')
+		default:
+			// Ignore unsupported extensions such as container id (`c:`) or timestamp (`T`),
+			// since the aggregated samples get the flush timestamp.
+		}
+	}
+	if tags := tagsPool[tagsStart:]; len(tags) > 0 {
+		r.Tags = tags[:len(tags):len(tags)]
+	}
+
+	if r.Type == TypeSet {
+		if len(valuesStr) == 0 {
+			return tagsPool, valuesPool, fmt.Errorf("set value cannot be empty")
+		}
+		r.SetValue = valuesStr
+		return tagsPool, valuesPool, nil
+	}
+	if r.Type == TypeGauge && len(valuesStr) > 0 && (valuesStr[0] == '+' || valuesStr[0] == '-') {
+		r.IsDelta = true
+	}
+	valuesStart := len(valuesPool)
+	for {
+		var valueStr string
+		n = strings.IndexByte(valuesStr, ':')
+		if n < 0 {
+			valueStr = valuesStr
+		} else {
+			valueStr = valuesStr[:n]
+			valuesStr = valuesStr[n+1:]
+		}
+		// fastfloat.Parse doesn't accept explicit plus sign, which is used for gauge deltas
+		valueStr = strings.TrimPrefix(valueStr, "+")
+		v, err := fastfloat.Parse(valueStr)
+		if err != nil {
+			return tagsPool, valuesPool, fmt.Errorf("cannot unmarshal metric value from %q: %w", valueStr, err)
+		}
+		valuesPool = append(valuesPool, v)
+		if n < 0 {
+			break
+		}
+	}
+	values := valuesPool[valuesStart:]
+	r.Values = values[:len(values):len(values)]
+	return tagsPool, valuesPool, nil
+}
+
+func unmarshalRows(dst []Row, s string, tagsPool []Tag, valuesPool []float64) ([]Row, []Tag, []float64) {
+	for len(s) > 0 {
+		n := strings.IndexByte(s, '\n')
+		if n < 0 {
+			// The last line.
+			return unmarshalRow(dst, s, tagsPool, valuesPool)
+		}
+		dst, tagsPool, valuesPool = unmarshalRow(dst, s[:n], tagsPool, valuesPool)
+		s = s[n+1:]
+	}
+	return dst, tagsPool, valuesPool
+}
+
+func unmarshalRow(dst []Row, s string, tagsPool []Tag, valuesPool []float64) ([]Row, []Tag, []float64) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		// Skip empty line
+		return dst, tagsPool, valuesPool
+	}
+	if cap(dst) > len(dst) {
+		dst = dst[:len(dst)+1]
+	} else {
+		dst = append(dst, Row{})
+	}
+	r := &dst[len(dst)-1]
+	var err error
+	tagsPool, valuesPool, err = r.unmarshal(s, tagsPool, valuesPool)
+	if err != nil {
+		dst = dst[:len(dst)-1]
+		logger.Errorf("cannot unmarshal statsd line %q: %s", s, err)
+		invalidLines.Inc()
+	}
+	return dst, tagsPool, valuesPool
+}
+
+var invalidLines = metrics.NewCounter(`vm_rows_invalid_total{type="statsd"}`)
+
+func unmarshalTags(dst []Tag, s string, tagsDelimiter, kvDelimiter byte) []Tag {
+	for len(s) > 0 {
+		var tagStr string
+		n := strings.IndexByte(s, tagsDelimiter)
+		if n < 0 {
+			tagStr = s
+			s = ""
+		} else {
+			tagStr = s[:n]
+			s = s[n+1:]
+		}
+		if len(tagStr) == 0 {
+			continue
+		}
+		var key, value string
+		if kvDelimiter == ':' {
+			key, value = datadogutil.SplitTag(tagStr)
+		} else {
+			n = strings.IndexByte(tagStr, kvDelimiter)
+			if n < 0 {
+				// Skip tag without value
+				continue
+			}
+			key, value = tagStr[:n], tagStr[n+1:]
+		}
+		if len(key) == 0 || len(value) == 0 {
+			// Skip empty tag
+			continue
+		}
+		dst = append(dst, Tag{
+			Key:   key,
+			Value: value,
+		})
+	}
+	return dst
+}
+
+// Tag is a statsd tag.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+func (t *Tag) reset() {
+	t.Key = ""
+	t.Value = ""
+}
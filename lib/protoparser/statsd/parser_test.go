@@ -0,0 +1,157 @@
+package statsd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowsUnmarshalFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		var rows Rows
+		rows.Unmarshal(s)
+		if len(rows.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows", len(rows.Rows))
+		}
+
+		// Try again
+		rows.Unmarshal(s)
+		if len(rows.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows", len(rows.Rows))
+		}
+	}
+
+	// Missing type
+	f("foo:1")
+	f("foo:1|")
+
+	// Unsupported type
+	f("foo:1|x")
+
+	// Missing metric name
+	f(":1|c")
+	f("1|c")
+	f(",a=b:1|c")
+
+	// Invalid value
+	f("foo:bar|c")
+	f("foo:|c")
+	f("foo:1:|ms")
+	f("foo:|s")
+
+	// Invalid sample rate
+	f("foo:1|c|@abc")
+	f("foo:1|c|@0")
+	f("foo:1|c|@1.5")
+}
+
+func TestRowsUnmarshalSuccess(t *testing.T) {
+	f := func(s string, rowsExpected *Rows) {
+		t.Helper()
+		var rows Rows
+		rows.Unmarshal(s)
+		if !reflect.DeepEqual(rows.Rows, rowsExpected.Rows) {
+			t.Fatalf("unexpected rows;\ngot\n%+v;\nwant\n%+v", rows.Rows, rowsExpected.Rows)
+		}
+
+		// Try unmarshaling again
+		rows.Unmarshal(s)
+		if !reflect.DeepEqual(rows.Rows, rowsExpected.Rows) {
+			t.Fatalf("unexpected rows on the second unmarshal;\ngot\n%+v;\nwant\n%+v", rows.Rows, rowsExpected.Rows)
+		}
+
+		rows.Reset()
+		if len(rows.Rows) != 0 {
+			t.Fatalf("non-empty rows after reset: %+v", rows.Rows)
+		}
+	}
+
+	// Empty line
+	f("", &Rows{})
+	f("\n\r\n", &Rows{})
+
+	// Counter
+	f("foo.bar:123|c", &Rows{
+		Rows: []Row{{
+			Metric:     "foo.bar",
+			Type:       TypeCounter,
+			Values:     []float64{123},
+			SampleRate: 1,
+		}},
+	})
+
+	// Counter with sample rate and DogStatsD tags
+	f("foo:2|c|@0.1|#env:prod,host:h1,novalue,:empty", &Rows{
+		Rows: []Row{{
+			Metric: "foo",
+			Tags: []Tag{
+				{Key: "env", Value: "prod"},
+				{Key: "host", Value: "h1"},
+				{Key: "novalue", Value: "no_label_value"},
+			},
+			Type:       TypeCounter,
+			Values:     []float64{2},
+			SampleRate: 0.1,
+		}},
+	})
+
+	// InfluxDB-style tags and unsupported extensions
+	f("foo,env=prod,bad:1.5|g|c:abc|T1656581400", &Rows{
+		Rows: []Row{{
+			Metric: "foo",
+			Tags: []Tag{
+				{Key: "env", Value: "prod"},
+			},
+			Type:       TypeGauge,
+			Values:     []float64{1.5},
+			SampleRate: 1,
+		}},
+	})
+
+	// Gauge deltas
+	f("foo:-3|g\nbar:+4|g", &Rows{
+		Rows: []Row{
+			{
+				Metric:     "foo",
+				Type:       TypeGauge,
+				Values:     []float64{-3},
+				IsDelta:    true,
+				SampleRate: 1,
+			},
+			{
+				Metric:     "bar",
+				Type:       TypeGauge,
+				Values:     []float64{4},
+				IsDelta:    true,
+				SampleRate: 1,
+			},
+		},
+	})
+
+	// Multiple values, sets and invalid lines in the middle
+	f("foo:1:2.5:3|ms\nbar:1|x\nbaz:user-1|s|#a:b\r\nqux:4|d", &Rows{
+		Rows: []Row{
+			{
+				Metric:     "foo",
+				Type:       TypeTimer,
+				Values:     []float64{1, 2.5, 3},
+				SampleRate: 1,
+			},
+			{
+				Metric: "baz",
+				Tags: []Tag{
+					{Key: "a", Value: "b"},
+				},
+				Type:       TypeSet,
+				SetValue:   "user-1",
+				SampleRate: 1,
+			},
+			{
+				Metric:     "qux",
+				Type:       TypeDistribution,
+				Values:     []float64{4},
+				SampleRate: 1,
+			},
+		},
+	})
+}
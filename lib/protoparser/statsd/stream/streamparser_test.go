@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/protoparserutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+)
+
+func TestParse(t *testing.T) {
+	protoparserutil.StartUnmarshalWorkers()
+	defer protoparserutil.StopUnmarshalWorkers()
+
+	f := func(s string, rowsExpected []statsd.Row) {
+		t.Helper()
+
+		var rowsLock sync.Mutex
+		var rows []statsd.Row
+		err := Parse(strings.NewReader(s), "", func(rs []statsd.Row) error {
+			rowsLock.Lock()
+			defer rowsLock.Unlock()
+			for _, r := range rs {
+				// Copy row values, since they are invalidated after the callback returns
+				if r.Tags != nil {
+					r.Tags = append([]statsd.Tag{}, r.Tags...)
+				}
+				r.Values = append([]float64{}, r.Values...)
+				rows = append(rows, r)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v;\nwant\n%+v", rows, rowsExpected)
+		}
+	}
+
+	f("foo:1|c|#a:b\nbar:2.5|ms\n", []statsd.Row{
+		{
+			Metric: "foo",
+			Tags: []statsd.Tag{
+				{Key: "a", Value: "b"},
+			},
+			Type:       statsd.TypeCounter,
+			Values:     []float64{1},
+			SampleRate: 1,
+		},
+		{
+			Metric:     "bar",
+			Type:       statsd.TypeTimer,
+			Values:     []float64{2.5},
+			SampleRate: 1,
+		},
+	})
+}
@@ -19,6 +19,9 @@ var maxLineLen = flagutil.NewBytes("import.maxLineLen", 10*1024*1024, "The maxim
 
 // Parse parses /api/v1/import lines from req and calls callback for the parsed rows.
 //
+// The data may be also passed as a top-level JSON array of objects. Such an array is parsed in a streaming manner
+// without loading the whole array in memory.
+//
 // The callback can be called concurrently multiple times for streamed data from reader.
 //
 // callback shouldn't hold rows after returning.
@@ -35,6 +38,10 @@ func Parse(r io.Reader, encoding string, callback func(rows []vmimport.Row) erro
 
 	ctx := getStreamContext(reader)
 	defer putStreamContext(ctx)
+	if err := ctx.initReader(); err != nil {
+		readErrors.Inc()
+		return fmt.Errorf("cannot read vmimport data: %w", err)
+	}
 	for ctx.Read() {
 		uw := getUnmarshalWork()
 		uw.ctx = ctx
@@ -56,7 +63,7 @@ func (ctx *streamContext) Read() bool {
 	if ctx.err != nil || ctx.hasCallbackError() {
 		return false
 	}
-	ctx.reqBuf, ctx.tailBuf, ctx.err = protoparserutil.ReadLinesBlockExt(ctx.br, ctx.reqBuf, ctx.tailBuf, maxLineLen.IntN())
+	ctx.reqBuf, ctx.tailBuf, ctx.err = protoparserutil.ReadLinesBlockExt(ctx.r, ctx.reqBuf, ctx.tailBuf, maxLineLen.IntN())
 	if ctx.err != nil {
 		if ctx.err != io.EOF {
 			readErrors.Inc()
@@ -75,6 +82,8 @@ var (
 
 type streamContext struct {
 	br      *bufio.Reader
+	jar     jsonArrayReader
+	r       io.Reader
 	reqBuf  []byte
 	tailBuf []byte
 	err     error
@@ -98,8 +107,35 @@ func (ctx *streamContext) hasCallbackError() bool {
 	return ok
 }
 
+// initReader detects whether the data is passed as a JSON array and initializes ctx.r accordingly.
+func (ctx *streamContext) initReader() error {
+	ctx.r = ctx.br
+	for {
+		c, err := ctx.br.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '[':
+			ctx.jar.r = ctx.br
+			ctx.jar.depth = 1
+			ctx.r = &ctx.jar
+			return nil
+		default:
+			return ctx.br.UnreadByte()
+		}
+	}
+}
+
 func (ctx *streamContext) reset() {
 	ctx.br.Reset(nil)
+	ctx.jar.reset()
+	ctx.r = nil
 	ctx.reqBuf = ctx.reqBuf[:0]
 	ctx.tailBuf = ctx.tailBuf[:0]
 	ctx.err = nil
@@ -124,6 +160,65 @@ func putStreamContext(ctx *streamContext) {
 
 var streamContextPool sync.Pool
 
+// jsonArrayReader converts the contents of a top-level JSON array of objects into JSON lines.
+//
+// The opening `[` must be already read from r.
+type jsonArrayReader struct {
+	r io.Reader
+
+	// depth is the nesting depth of the current position. The top-level array has depth 1.
+	depth    int
+	inString bool
+	escape   bool
+}
+
+func (jar *jsonArrayReader) reset() {
+	jar.r = nil
+	jar.depth = 0
+	jar.inString = false
+	jar.escape = false
+}
+
+// Read implements io.Reader.
+//
+// It replaces commas between array items and the closing `]` with newlines,
+// while newlines inside array items are replaced with whitespace.
+func (jar *jsonArrayReader) Read(p []byte) (int, error) {
+	n, err := jar.r.Read(p)
+	for i, c := range p[:n] {
+		if jar.inString {
+			if jar.escape {
+				jar.escape = false
+			} else if c == '\\' {
+				jar.escape = true
+			} else if c == '"' {
+				jar.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			jar.inString = true
+		case '{', '[':
+			jar.depth++
+		case '}', ']':
+			jar.depth--
+			if jar.depth == 0 {
+				p[i] = '\n'
+			}
+		case ',':
+			if jar.depth == 1 {
+				p[i] = '\n'
+			}
+		case '\n', '\r':
+			if jar.depth > 1 {
+				p[i] = ' '
+			}
+		}
+	}
+	return n, err
+}
+
 type unmarshalWork struct {
 	rows     vmimport.Rows
 	ctx      *streamContext
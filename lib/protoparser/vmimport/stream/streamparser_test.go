@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/protoparserutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/vmimport"
+)
+
+func TestJSONArrayReader(t *testing.T) {
+	f := func(data, resultExpected string) {
+		t.Helper()
+
+		// Read the data byte-by-byte in order to verify that the state is properly preserved between Read calls
+		var jar jsonArrayReader
+		jar.r = iotest.OneByteReader(strings.NewReader(data))
+		jar.depth = 1
+		result, err := io.ReadAll(&jar)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result;\ngot\n%q\nwant\n%q", result, resultExpected)
+		}
+	}
+
+	f(``, ``)
+	f(`]`, "\n")
+	f(`{"a":1},{"b":[1,2]}]`, "{\"a\":1}\n{\"b\":[1,2]}\n")
+	f("\n  {\n  \"a\": \"x,]\\\"}\"\n },\n{\"b\":2}\n]\n", "\n  {   \"a\": \"x,]\\\"}\"  }\n\n{\"b\":2}\n\n\n")
+}
+
+func TestParse(t *testing.T) {
+	protoparserutil.StartUnmarshalWorkers()
+	defer protoparserutil.StopUnmarshalWorkers()
+
+	f := func(data, resultExpected string) {
+		t.Helper()
+
+		var mu sync.Mutex
+		var lines []string
+		err := Parse(bytes.NewBufferString(data), "", func(rows []vmimport.Row) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, r := range rows {
+				var tags []string
+				for _, tag := range r.Tags {
+					tags = append(tags, fmt.Sprintf("%s=%q", tag.Key, tag.Value))
+				}
+				lines = append(lines, fmt.Sprintf("{%s} %v %v", strings.Join(tags, ","), r.Values, r.Timestamps))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sort.Strings(lines)
+		result := strings.Join(lines, "\n")
+		if result != resultExpected {
+			t.Fatalf("unexpected result;\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	// Empty data
+	f(``, ``)
+	f(" \n", ``)
+	f(`[]`, ``)
+
+	// JSON lines
+	f(`{"metric":{"__name__":"foo"},"values":[1,2],"timestamps":[10,20]}
+{"metric":{"__name__":"bar","job":"x"},"values":[3],"timestamps":[30]}`, `{__name__="bar",job="x"} [3] [30]
+{__name__="foo"} [1 2] [10 20]`)
+
+	// JSON array
+	f(`[{"metric":{"__name__":"foo"},"values":[1,2],"timestamps":[10,20]},{"metric":{"__name__":"bar","job":"x"},"values":[3],"timestamps":[30]}]`, `{__name__="bar",job="x"} [3] [30]
+{__name__="foo"} [1 2] [10 20]`)
+
+	// Pretty-printed JSON array
+	f(`
+[
+  {
+    "metric": {"__name__": "foo", "path": "/a,b]"},
+    "values": [1],
+    "timestamps": [10]
+  },
+  {
+    "metric": {"__name__": "bar"},
+    "values": [2],
+    "timestamps": [20]
+  }
+]
+`, `{__name__="bar"} [2] [20]
+{__name__="foo",path="/a,b]"} [1] [10]`)
+}
@@ -0,0 +1,66 @@
+package streamaggr
+
+import (
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/cespare/xxhash/v2"
+)
+
+// countSeriesActiveAggrValueShared holds delete deadlines per each unique input series.
+//
+// It is shared between blue and green states if enable_windows is set.
+type countSeriesActiveAggrValueShared struct {
+	deleteDeadlines map[uint64]int64
+}
+
+// countSeriesActiveAggrValue calculates output=count_series_active, e.g. the number of unique series,
+// which received samples during the last staleness_interval.
+type countSeriesActiveAggrValue struct {
+	shared *countSeriesActiveAggrValueShared
+}
+
+func (av *countSeriesActiveAggrValue) pushSample(_ aggrConfig, _ *pushSample, key string, deleteDeadline int64) {
+	// Track unique hashes over the keys instead of unique key values.
+	// This reduces memory usage at the cost of possible hash collisions for distinct key values.
+	h := xxhash.Sum64(bytesutil.ToUnsafeBytes(key))
+	av.shared.deleteDeadlines[h] = deleteDeadline
+}
+
+func (av *countSeriesActiveAggrValue) flush(_ aggrConfig, ctx *flushCtx, key string, isLast bool) {
+	dds := av.shared.deleteDeadlines
+	for h, deleteDeadline := range dds {
+		if ctx.flushTimestamp > deleteDeadline || isLast {
+			delete(dds, h)
+		}
+	}
+	if len(dds) > 0 {
+		ctx.appendSeries(key, "count_series_active", float64(len(dds)))
+	}
+}
+
+func (av *countSeriesActiveAggrValue) state() any {
+	return av.shared
+}
+
+func newCountSeriesActiveAggrConfig(useSharedState bool) aggrConfig {
+	return &countSeriesActiveAggrConfig{
+		useSharedState: useSharedState,
+	}
+}
+
+type countSeriesActiveAggrConfig struct {
+	useSharedState bool
+}
+
+func (ac *countSeriesActiveAggrConfig) getValue(s any) aggrValue {
+	var shared *countSeriesActiveAggrValueShared
+	if !ac.useSharedState || s == nil {
+		shared = &countSeriesActiveAggrValueShared{
+			deleteDeadlines: make(map[uint64]int64),
+		}
+	} else {
+		shared = s.(*countSeriesActiveAggrValueShared)
+	}
+	return &countSeriesActiveAggrValue{
+		shared: shared,
+	}
+}
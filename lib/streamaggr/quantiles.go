@@ -6,7 +6,7 @@ import (
 	"strconv"
 )
 
-// quantilesAggrValue calculates output=quantiles, e.g. the given quantiles over the input samples.
+// quantilesAggrValue calculates output=quantiles and output=quantile, e.g. the given quantiles over the input samples.
 type quantilesAggrValue struct {
 	h *histogram.Fast
 }
@@ -29,7 +29,7 @@ func (av *quantilesAggrValue) flush(c aggrConfig, ctx *flushCtx, key string, _ b
 		for i, quantile := range ac.quantiles {
 			ac.b = strconv.AppendFloat(ac.b[:0], ac.phis[i], 'g', -1, 64)
 			phiStr := bytesutil.InternBytes(ac.b)
-			ctx.appendSeriesWithExtraLabel(key, "quantiles", quantile, "quantile", phiStr)
+			ctx.appendSeriesWithExtraLabel(key, ac.suffix, quantile, "quantile", phiStr)
 		}
 	}
 }
@@ -38,13 +38,15 @@ func (*quantilesAggrValue) state() any {
 	return nil
 }
 
-func newQuantilesAggrConfig(phis []float64) aggrConfig {
+func newQuantilesAggrConfig(suffix string, phis []float64) aggrConfig {
 	return &quantilesAggrConfig{
-		phis: phis,
+		suffix: suffix,
+		phis:   phis,
 	}
 }
 
 type quantilesAggrConfig struct {
+	suffix    string
 	phis      []float64
 	quantiles []float64
 	b         []byte
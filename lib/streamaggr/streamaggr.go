@@ -35,12 +35,14 @@ var supportedOutputs = []string{
 	"avg",
 	"count_samples",
 	"count_series",
+	"count_series_active",
 	"histogram_bucket",
 	"increase",
 	"increase_prometheus",
 	"last",
 	"max",
 	"min",
+	"quantile(phi)",
 	"quantiles(phi1, ..., phiN)",
 	"rate_avg",
 	"rate_sum",
@@ -194,12 +196,14 @@ type Config struct {
 	// - avg - the average value across all the samples
 	// - count_samples - counts the input samples
 	// - count_series - counts the number of unique input series
+	// - count_series_active - counts the number of unique input series received during the last staleness_interval
 	// - histogram_bucket - creates VictoriaMetrics histogram for input samples
 	// - increase - calculates the increase over input series
 	// - increase_prometheus - calculates the increase over input series, ignoring the first sample in new time series
 	// - last - the last biggest sample value
 	// - max - the maximum sample value
 	// - min - the minimum sample value
+	// - quantile(phi) - quantile estimation for phi in the range [0..1]
 	// - quantiles(phi1, ..., phiN) - quantiles' estimation for phi in the range [0..1]
 	// - rate_avg - calculates average of rate for input counters
 	// - rate_sum - calculates sum of rate for input counters
@@ -724,31 +728,29 @@ func newOutputConfig(output string, outputsSeen map[string]struct{}, useSharedSt
 	outputsSeen[output] = struct{}{}
 
 	if strings.HasPrefix(output, "quantiles(") {
-		if !strings.HasSuffix(output, ")") {
-			return nil, fmt.Errorf("missing closing brace for `quantiles()` output")
-		}
-		argsStr := output[len("quantiles(") : len(output)-1]
-		if len(argsStr) == 0 {
-			return nil, fmt.Errorf("`quantiles()` must contain at least one phi")
-		}
-		args := strings.Split(argsStr, ",")
-		phis := make([]float64, len(args))
-		for i, arg := range args {
-			arg = strings.TrimSpace(arg)
-			phi, err := strconv.ParseFloat(arg, 64)
-			if err != nil {
-				return nil, fmt.Errorf("cannot parse phi=%q for quantiles(%s): %w", arg, argsStr, err)
-			}
-			if phi < 0 || phi > 1 {
-				return nil, fmt.Errorf("phi inside quantiles(%s) must be in the range [0..1]; got %v", argsStr, phi)
-			}
-			phis[i] = phi
+		phis, err := parseQuantilePhis("quantiles", output)
+		if err != nil {
+			return nil, err
 		}
 		if _, ok := outputsSeen["quantiles"]; ok {
 			return nil, fmt.Errorf("`outputs` list contains duplicated `quantiles()` function, please combine multiple phi* like `quantiles(0.5, 0.9)`")
 		}
 		outputsSeen["quantiles"] = struct{}{}
-		return newQuantilesAggrConfig(phis), nil
+		return newQuantilesAggrConfig("quantiles", phis), nil
+	}
+	if strings.HasPrefix(output, "quantile(") {
+		phis, err := parseQuantilePhis("quantile", output)
+		if err != nil {
+			return nil, err
+		}
+		if len(phis) != 1 {
+			return nil, fmt.Errorf("`quantile()` must contain exactly one phi; use `quantiles(%s)` for calculating multiple quantiles", output[len("quantile("):len(output)-1])
+		}
+		if _, ok := outputsSeen["quantile"]; ok {
+			return nil, fmt.Errorf("`outputs` list contains duplicated `quantile()` function, please combine multiple phi* like `quantiles(0.5, 0.9)`")
+		}
+		outputsSeen["quantile"] = struct{}{}
+		return newQuantilesAggrConfig("quantile", phis), nil
 	}
 	ignoreFirstSampleIntervalSecs := uint64(ignoreFirstSampleInterval.Seconds())
 
@@ -759,6 +761,8 @@ func newOutputConfig(output string, outputsSeen map[string]struct{}, useSharedSt
 		return newCountSamplesAggrConfig(), nil
 	case "count_series":
 		return newCountSeriesAggrConfig(), nil
+	case "count_series_active":
+		return newCountSeriesActiveAggrConfig(useSharedState), nil
 	case "histogram_bucket":
 		return newHistogramBucketAggrConfig(useSharedState), nil
 	case "increase":
@@ -792,6 +796,31 @@ func newOutputConfig(output string, outputsSeen map[string]struct{}, useSharedSt
 	}
 }
 
+// parseQuantilePhis parses phi args for the given funcName from output, e.g. `quantiles(0.5, 0.9)`.
+func parseQuantilePhis(funcName, output string) ([]float64, error) {
+	if !strings.HasSuffix(output, ")") {
+		return nil, fmt.Errorf("missing closing brace for `%s()` output", funcName)
+	}
+	argsStr := output[len(funcName)+1 : len(output)-1]
+	if len(argsStr) == 0 {
+		return nil, fmt.Errorf("`%s()` must contain at least one phi", funcName)
+	}
+	args := strings.Split(argsStr, ",")
+	phis := make([]float64, len(args))
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		phi, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse phi=%q for %s(%s): %w", arg, funcName, argsStr, err)
+		}
+		if phi < 0 || phi > 1 {
+			return nil, fmt.Errorf("phi inside %s(%s) must be in the range [0..1]; got %v", funcName, argsStr, phi)
+		}
+		phis[i] = phi
+	}
+	return phis, nil
+}
+
 func (a *aggregator) runFlusher(pushFunc PushFunc, alignFlushToInterval, skipIncompleteFlush bool, ignoreFirstIntervals int) {
 	minTime := time.UnixMilli(a.minDeadline.Load())
 	flushTime := minTime.Add(a.interval)
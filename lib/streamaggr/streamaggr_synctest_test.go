@@ -634,6 +634,36 @@ cpu_usage:1m_without_cpu_quantiles{quantile="1"} 90
   outputs: ["quantiles(0, 0.5, 1)"]
 `, "1111111")
 
+	// quantile output
+	f([]string{`
+cpu_usage{cpu="1"} 12.5
+cpu_usage{cpu="1"} 13.3
+cpu_usage{cpu="1"} 13
+cpu_usage{cpu="1"} 12
+cpu_usage{cpu="1"} 14
+cpu_usage{cpu="1"} 25
+cpu_usage{cpu="2"} 90
+`}, time.Minute, `cpu_usage:1m_quantile{cpu="1",quantile="0.5"} 13.3
+cpu_usage:1m_quantile{cpu="2",quantile="0.5"} 90
+`, `
+- interval: 1m
+  outputs: ["quantile(0.5)"]
+`, "1111111")
+
+	// count_series_active output
+	f([]string{`
+foo{abc="123"} 4
+foo{abc="456"} 5
+foo{abc="123"} 6
+bar 1
+`}, time.Minute, `bar:1m_without_abc_count_series_active 1
+foo:1m_without_abc_count_series_active 2
+`, `
+- interval: 1m
+  without: [abc]
+  outputs: [count_series_active]
+`, "1111")
+
 	// append additional label
 	f([]string{`
 foo{abc="123"} 4
@@ -181,6 +181,30 @@ func TestAggregatorsFailure(t *testing.T) {
 - interval: 1m
   outputs: ["quantiles(1.5)"]
 `)
+
+	// Invalid quantile()
+	f(`
+- interval: 1m
+  outputs: ["quantile("]
+`)
+	f(`
+- interval: 1m
+  outputs: ["quantile()"]
+`)
+	f(`
+- interval: 1m
+  outputs: ["quantile(1.5)"]
+`)
+	f(`
+- interval: 1m
+  outputs: ["quantile(0.5, 0.9)"]
+`)
+	// "quantile(0.5)", "quantile(0.9)" should be set as "quantiles(0.5, 0.9)"
+	f(`
+- interval: 1m
+  outputs: ["quantile(0.5)", "quantile(0.9)"]
+`)
+
 	f(`
 - interval: 1m
   outputs: [total, total]